@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// insertBatchSize bounds the multi-row INSERT statements of the fallback path.
+const insertBatchSize = 1000
+
+// BulkCopy streams rows into table, like BulkCopyCtx.
+func BulkCopy(conn sqlx.SqlConn, table string, columns []string,
+	rows <-chan []interface{}) (int64, error) {
+	return BulkCopyCtx(context.Background(), conn, table, columns, rows)
+}
+
+// BulkCopyCtx streams rows into table via the postgres COPY protocol, which
+// loads bulk data far faster than batched INSERTs, falling back to multi-row
+// INSERT batches when the driver doesn't support COPY. The whole load runs in
+// one transaction on the master and the number of rows written is returned.
+// The rows channel must be closed by the producer to finish the load.
+func BulkCopyCtx(ctx context.Context, conn sqlx.SqlConn, table string,
+	columns []string, rows <-chan []interface{}) (int64, error) {
+	db, err := conn.RawDB()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := copyRows(ctx, tx, table, columns, rows)
+	if err != nil {
+		if e := tx.Rollback(); e != nil {
+			return 0, fmt.Errorf("bulk copy failed: %s, rollback failed: %w", err, e)
+		}
+		return 0, err
+	}
+
+	return total, tx.Commit()
+}
+
+func copyRows(ctx context.Context, tx *sql.Tx, table string, columns []string,
+	rows <-chan []interface{}) (int64, error) {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return insertRows(ctx, tx, table, columns, rows)
+	}
+	defer stmt.Close()
+
+	var total int64
+	for row := range rows {
+		if len(row) != len(columns) {
+			return total, fmt.Errorf("bulk copy: row has %d values, want %d", len(row), len(columns))
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return total, err
+		}
+		total++
+	}
+
+	// an exec without args flushes the buffered rows to the server
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return total, err
+	}
+
+	return total, nil
+}
+
+func insertRows(ctx context.Context, tx *sql.Tx, table string, columns []string,
+	rows <-chan []interface{}) (int64, error) {
+	var total int64
+	batch := make([][]interface{}, 0, insertBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		query, args := buildInsertBatch(table, columns, batch)
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		total += affected
+		batch = batch[:0]
+		return nil
+	}
+
+	for row := range rows {
+		if len(row) != len(columns) {
+			return total, fmt.Errorf("bulk copy: row has %d values, want %d", len(row), len(columns))
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= insertBatchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, flush()
+}
+
+func buildInsertBatch(table string, columns []string, batch [][]interface{}) (
+	string, []interface{}) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	args := make([]interface{}, 0, len(batch)*len(columns))
+	for i, row := range batch {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteByte('(')
+		for j := range columns {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			fmt.Fprintf(&builder, "$%d", i*len(columns)+j+1)
+		}
+		builder.WriteByte(')')
+		args = append(args, row...)
+	}
+
+	return builder.String(), args
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestBulkCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`COPY "users" \("id", "name"\) FROM STDIN`)
+	prepare.ExpectExec().WithArgs(1, "first").WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WithArgs(2, "second").WillReturnResult(sqlmock.NewResult(0, 0))
+	prepare.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	rows := make(chan []interface{}, 2)
+	rows <- []interface{}{1, "first"}
+	rows <- []interface{}{2, "second"}
+	close(rows)
+
+	total, err := BulkCopy(sqlx.NewSqlConnFromDB(db), "users", []string{"id", "name"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBulkCopyFallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`COPY "users" \("id", "name"\) FROM STDIN`).
+		WillReturnError(assert.AnError)
+	mock.ExpectExec(`INSERT INTO users \(id, name\) VALUES \(\$1, \$2\), \(\$3, \$4\)`).
+		WithArgs(1, "first", 2, "second").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	rows := make(chan []interface{}, 2)
+	rows <- []interface{}{1, "first"}
+	rows <- []interface{}{2, "second"}
+	close(rows)
+
+	total, err := BulkCopy(sqlx.NewSqlConnFromDB(db), "users", []string{"id", "name"}, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -606,7 +606,8 @@ func (d dummySqlConn) QueryRowsPartialCtx(ctx context.Context, v interface{}, qu
 	return nil
 }
 
-func (d dummySqlConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+func (d dummySqlConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error,
+	opts ...sqlx.TransactOption) error {
 	return nil
 }
 
@@ -614,6 +615,10 @@ func (d dummySqlConn) Exec(query string, args ...interface{}) (sql.Result, error
 	return nil, nil
 }
 
+func (d dummySqlConn) InTransaction() bool {
+	return false
+}
+
 func (d dummySqlConn) Prepare(query string) (sqlx.StmtSession, error) {
 	return nil, nil
 }
@@ -641,11 +646,15 @@ func (d dummySqlConn) QueryRowsPartial(v interface{}, query string, args ...inte
 	return nil
 }
 
+func (d dummySqlConn) Ping(_ context.Context) error {
+	return nil
+}
+
 func (d dummySqlConn) RawDB() (*sql.DB, error) {
 	return nil, nil
 }
 
-func (d dummySqlConn) Transact(func(session sqlx.Session) error) error {
+func (d dummySqlConn) Transact(func(session sqlx.Session) error, ...sqlx.TransactOption) error {
 	return nil
 }
 
@@ -678,13 +687,14 @@ func (c *trackedConn) RawDB() (*sql.DB, error) {
 	return nil, nil
 }
 
-func (c *trackedConn) Transact(fn func(session sqlx.Session) error) error {
+func (c *trackedConn) Transact(fn func(session sqlx.Session) error, opts ...sqlx.TransactOption) error {
 	return c.TransactCtx(context.Background(), func(_ context.Context, session sqlx.Session) error {
 		return fn(session)
 	})
 }
 
-func (c *trackedConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+func (c *trackedConn) TransactCtx(ctx context.Context, fn func(context.Context, sqlx.Session) error,
+	opts ...sqlx.TransactOption) error {
 	c.transactValue = true
 	return c.dummySqlConn.TransactCtx(ctx, fn)
 }
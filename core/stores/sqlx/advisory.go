@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrAdvisoryLockFailed is an error that indicates the advisory lock was
+	// not acquired.
+	ErrAdvisoryLockFailed = errors.New("advisory lock not acquired")
+	// ErrAdvisoryLockUnsupported is an error that indicates the session can't
+	// take advisory locks, it's not a transaction session of a mysql or
+	// postgres connection.
+	ErrAdvisoryLockUnsupported = errors.New("advisory locks require a transaction session on mysql or postgres")
+)
+
+// AdvisoryLock acquires the advisory lock for key, like AdvisoryLockCtx.
+func AdvisoryLock(session Session, key int64) error {
+	return AdvisoryLockCtx(context.Background(), session, key)
+}
+
+// AdvisoryLockCtx blocks until the cross-process advisory lock for key is held
+// by session's transaction, centralizing the dialect-specific lock SQL.
+// Running the lock on the transaction session pins it to the same connection
+// as the guarded statements, which a pool doesn't guarantee otherwise.
+// On postgres it takes pg_advisory_xact_lock, which releases automatically at
+// commit or rollback. On mysql it takes GET_LOCK, which is connection-scoped:
+// it's released right before commit as a safety net, but a rollback path
+// should release it explicitly with AdvisoryUnlockCtx, or the pooled
+// connection escapes still holding the lock.
+func AdvisoryLockCtx(ctx context.Context, session Session, key int64) error {
+	hooked, ok := dialectSession(session)
+	if !ok {
+		return ErrAdvisoryLockUnsupported
+	}
+
+	switch hooked.driverName {
+	case postgresDriverName:
+		_, err := session.ExecCtx(ctx, "SELECT pg_advisory_xact_lock($1)", key)
+		return err
+	case mysqlDriverName:
+		tx, ok := underlyingTx(session)
+		if !ok {
+			return ErrAdvisoryLockUnsupported
+		}
+
+		var acquired sql.NullInt64
+		if err := tx.Tx.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)",
+			mysqlLockName(key)).Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return ErrAdvisoryLockFailed
+		}
+
+		// the lock survives commit on mysql, release it before the pooled
+		// connection escapes with it
+		hooked.BeforeCommit(func() error {
+			var released sql.NullInt64
+			return tx.Tx.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)",
+				mysqlLockName(key)).Scan(&released)
+		})
+
+		return nil
+	default:
+		return ErrAdvisoryLockUnsupported
+	}
+}
+
+// AdvisoryUnlock releases the advisory lock for key, like AdvisoryUnlockCtx.
+func AdvisoryUnlock(session Session, key int64) error {
+	return AdvisoryUnlockCtx(context.Background(), session, key)
+}
+
+// AdvisoryUnlockCtx releases the advisory lock for key before the transaction
+// ends. On postgres it's a no-op, transaction-scoped locks only release at
+// commit or rollback. On mysql releasing a lock that isn't held is not an
+// error, so it's safe to defer.
+func AdvisoryUnlockCtx(ctx context.Context, session Session, key int64) error {
+	hooked, ok := dialectSession(session)
+	if !ok {
+		return ErrAdvisoryLockUnsupported
+	}
+
+	switch hooked.driverName {
+	case postgresDriverName:
+		return nil
+	case mysqlDriverName:
+		tx, ok := underlyingTx(session)
+		if !ok {
+			return ErrAdvisoryLockUnsupported
+		}
+
+		var released sql.NullInt64
+		return tx.Tx.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)",
+			mysqlLockName(key)).Scan(&released)
+	default:
+		return ErrAdvisoryLockUnsupported
+	}
+}
+
+// mysqlLockName maps the numeric key to the string names GET_LOCK works with.
+func mysqlLockName(key int64) string {
+	return fmt.Sprintf("sqlx:advisory:%d", key)
+}
+
+// dialectSession unwraps session wrappers down to the hooked session carrying
+// the driver dialect.
+func dialectSession(session Session) (hookedSession, bool) {
+	switch s := session.(type) {
+	case hookedSession:
+		return s, true
+	case readOnlyGuard:
+		return dialectSession(s.trans)
+	case implicitCommitGuard:
+		return dialectSession(s.trans)
+	case stallGuard:
+		return dialectSession(s.trans)
+	default:
+		return hookedSession{}, false
+	}
+}
@@ -0,0 +1,99 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisoryLockMysql(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT GET_LOCK\(\?, -1\)`).WithArgs("sqlx:advisory:7").
+			WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(1))
+		mock.ExpectExec(`update users set name = \?`).WithArgs("any").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// the safety-net hook releases the connection-scoped lock before commit
+		mock.ExpectQuery(`SELECT RELEASE_LOCK\(\?\)`).WithArgs("sqlx:advisory:7").
+			WillReturnRows(sqlmock.NewRows([]string{"RELEASE_LOCK"}).AddRow(1))
+		mock.ExpectCommit()
+
+		opts := buildTxOptions()
+		opts.driverName = mysqlDriverName
+		err := transactOnConn(context.Background(), db, begin, opts,
+			func(ctx context.Context, session Session) error {
+				if err := AdvisoryLockCtx(ctx, session, 7); err != nil {
+					return err
+				}
+				_, err := session.ExecCtx(ctx, "update users set name = ?", "any")
+				return err
+			})
+		assert.Nil(t, err)
+	})
+}
+
+func TestAdvisoryLockMysqlNotAcquired(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT GET_LOCK\(\?, -1\)`).WithArgs("sqlx:advisory:7").
+			WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(0))
+		mock.ExpectRollback()
+
+		opts := buildTxOptions()
+		opts.driverName = mysqlDriverName
+		err := transactOnConn(context.Background(), db, begin, opts,
+			func(ctx context.Context, session Session) error {
+				return AdvisoryLockCtx(ctx, session, 7)
+			})
+		assert.ErrorIs(t, err, ErrAdvisoryLockFailed)
+	})
+}
+
+func TestAdvisoryLockPostgres(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		opts := buildTxOptions()
+		opts.driverName = postgresDriverName
+		err := transactOnConn(context.Background(), db, begin, opts,
+			func(ctx context.Context, session Session) error {
+				if err := AdvisoryLockCtx(ctx, session, 7); err != nil {
+					return err
+				}
+				// transaction-scoped locks release at commit, unlock is a no-op
+				return AdvisoryUnlockCtx(ctx, session, 7)
+			})
+		assert.Nil(t, err)
+	})
+}
+
+func TestAdvisoryLockUnsupportedSession(t *testing.T) {
+	assert.Equal(t, ErrAdvisoryLockUnsupported, AdvisoryLock(txSession{}, 7))
+	assert.Equal(t, ErrAdvisoryLockUnsupported, AdvisoryUnlock(txSession{}, 7))
+}
+
+func TestAdvisoryLockMysqlThroughGuards(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT GET_LOCK\(\?, -1\)`).WithArgs("sqlx:advisory:7").
+			WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(1))
+		mock.ExpectQuery(`SELECT RELEASE_LOCK\(\?\)`).WithArgs("sqlx:advisory:7").
+			WillReturnRows(sqlmock.NewRows([]string{"RELEASE_LOCK"}).AddRow(1))
+		mock.ExpectCommit()
+
+		// the implicit-commit guard must not hide the underlying transaction
+		opts := buildTxOptions(WithWarnImplicitCommit())
+		opts.driverName = mysqlDriverName
+		err := transactOnConn(context.Background(), db, begin, opts,
+			func(ctx context.Context, session Session) error {
+				return AdvisoryLockCtx(ctx, session, 7)
+			})
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,20 @@
+package sqlx
+
+import "context"
+
+type ambientTxKey struct{}
+
+// WithTx stores session as the ambient transaction of ctx. Conn-level calls
+// made with the returned context run on session instead of the conn, so
+// repository methods join a caller's transaction without threading a Session
+// through every signature. session must be a transaction session, storing a
+// SqlConn would make its calls loop back to themselves.
+func WithTx(ctx context.Context, session Session) context.Context {
+	return context.WithValue(ctx, ambientTxKey{}, session)
+}
+
+// TxFromContext returns the ambient transaction session of ctx, if any.
+func TxFromContext(ctx context.Context) (Session, bool) {
+	session, ok := ctx.Value(ambientTxKey{}).(Session)
+	return session, ok
+}
@@ -0,0 +1,44 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTxDelegates(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("select count").WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, conn.TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				ctx = WithTx(ctx, session)
+
+				// conn-level calls must run inside the transaction
+				if _, err := conn.ExecCtx(ctx, "update users set name = 'a'"); err != nil {
+					return err
+				}
+
+				var count int
+				return conn.QueryRowCtx(ctx, &count, "select count(*) from users")
+			}))
+	})
+}
+
+func TestTxFromContext(t *testing.T) {
+	_, ok := TxFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithTx(context.Background(), txSession{})
+	session, ok := TxFromContext(ctx)
+	assert.True(t, ok)
+	assert.NotNil(t, session)
+}
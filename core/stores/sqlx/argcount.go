@@ -0,0 +1,108 @@
+package sqlx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ErrArgCountMismatch is an error that indicates the number of args doesn't
+// match the number of placeholders in the query.
+type ErrArgCountMismatch struct {
+	Want int
+	Got  int
+}
+
+func (e ErrArgCountMismatch) Error() string {
+	return fmt.Sprintf("query expects %d args, got %d", e.Want, e.Got)
+}
+
+// WithValidateArgCount counts the placeholders in each query, skipping quoted
+// literals and comments, and fails with ErrArgCountMismatch before hitting the
+// database when len(args) doesn't match, turning a cryptic driver error into
+// an obvious one. Both `?` and numbered `$N`/`:N` placeholders are supported.
+// Opt-in since it parses every statement.
+func WithValidateArgCount() SqlOption {
+	return func(conn *commonSqlConn) {
+		prev := conn.argValidator
+		conn.argValidator = func(query string, args []interface{}) error {
+			if prev != nil {
+				if err := prev(query, args); err != nil {
+					return err
+				}
+			}
+
+			return validateArgCount(query, args)
+		}
+	}
+}
+
+func validateArgCount(query string, args []interface{}) error {
+	if containsNamedArgs(args) {
+		return nil
+	}
+
+	want := countPlaceholders(query)
+	if want != len(args) {
+		return ErrArgCountMismatch{
+			Want: want,
+			Got:  len(args),
+		}
+	}
+
+	return nil
+}
+
+// countPlaceholders returns the number of args the query binds, counting `?`
+// occurrences and the highest numbered `$N`/`:N` index, outside of quoted
+// literals and comments.
+func countPlaceholders(query string) int {
+	var count, maxIndex int
+	bytes := len(query)
+
+	for i := 0; i < bytes; i++ {
+		switch ch := query[i]; ch {
+		case '?':
+			count++
+		case ':', '$':
+			var j int
+			for j = i + 1; j < bytes; j++ {
+				if char := query[j]; char < '0' || '9' < char {
+					break
+				}
+			}
+
+			if j > i+1 {
+				if index, err := strconv.Atoi(query[i+1 : j]); err == nil && index > maxIndex {
+					maxIndex = index
+				}
+				i = j - 1
+			}
+		case '\'', '"', '`':
+			for j := i + 1; j < bytes; j++ {
+				if cur := query[j]; cur == '\\' {
+					j++
+				} else if cur == ch {
+					i = j
+					break
+				}
+			}
+		case '-':
+			if i+1 < bytes && query[i+1] == '-' {
+				for i += 2; i < bytes && query[i] != '\n'; i++ {
+				}
+			}
+		case '/':
+			if i+1 < bytes && query[i+1] == '*' {
+				for i += 2; i+1 < bytes && (query[i] != '*' || query[i+1] != '/'); i++ {
+				}
+				i++
+			}
+		}
+	}
+
+	if maxIndex > count {
+		return maxIndex
+	}
+
+	return count
+}
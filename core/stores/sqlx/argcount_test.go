@@ -0,0 +1,94 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func TestCountPlaceholders(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{
+			name:  "question marks",
+			query: "SELECT * FROM users WHERE id = ? AND name = ?",
+			want:  2,
+		},
+		{
+			name:  "numbered postgres",
+			query: "SELECT * FROM users WHERE id = $1 AND name = $2 OR alias = $2",
+			want:  2,
+		},
+		{
+			name:  "numbered oracle",
+			query: "SELECT * FROM users WHERE id = :1 AND name = :2",
+			want:  2,
+		},
+		{
+			name:  "quoted literal skipped",
+			query: "SELECT * FROM users WHERE name = '?' AND id = ?",
+			want:  1,
+		},
+		{
+			name:  "escaped quote in literal",
+			query: `SELECT * FROM users WHERE name = 'it\'s ?' AND id = ?`,
+			want:  1,
+		},
+		{
+			name:  "line comment skipped",
+			query: "SELECT * FROM users -- where id = ?\nWHERE id = ?",
+			want:  1,
+		},
+		{
+			name:  "block comment skipped",
+			query: "SELECT * FROM users /* id = ? */ WHERE id = ?",
+			want:  1,
+		},
+		{
+			name:  "no placeholders",
+			query: "SELECT * FROM users",
+			want:  0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, countPlaceholders(test.query))
+		})
+	}
+}
+
+func TestValidateArgCount(t *testing.T) {
+	err := validateArgCount("SELECT * FROM users WHERE id = ? AND name = ?", []interface{}{1})
+	assert.Equal(t, ErrArgCountMismatch{Want: 2, Got: 1}, err)
+	assert.Equal(t, "query expects 2 args, got 1", err.Error())
+	assert.Nil(t, validateArgCount("SELECT * FROM users WHERE id = ?", []interface{}{1}))
+	assert.Nil(t, validateArgCount("SELECT * FROM users WHERE id = :id",
+		[]interface{}{sql.Named("id", 1)}))
+}
+
+func TestWithValidateArgCount(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := &commonSqlConn{
+			connProv: func(ds string) (*sql.DB, error) {
+				return db, nil
+			},
+			brk: breaker.NewBreaker(),
+		}
+		WithValidateArgCount()(conn)
+
+		_, err := conn.Exec("UPDATE users SET name = ? WHERE id = ?", "first")
+		assert.Equal(t, ErrArgCountMismatch{Want: 2, Got: 1}, err)
+
+		mock.ExpectExec(`UPDATE users SET name = \? WHERE id = \?`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err = conn.Exec("UPDATE users SET name = ? WHERE id = ?", "first", 1)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,129 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+	"github.com/zeromicro/go-zero/core/timex"
+)
+
+// defaultExplainInterval limits plan capture to once per query template per interval,
+// to avoid doubling the read load during incidents.
+const defaultExplainInterval = time.Minute
+
+type autoExplain struct {
+	threshold time.Duration
+	interval  time.Duration
+	lock      sync.Mutex
+	lastRuns  map[string]time.Duration
+}
+
+// WithAutoExplain makes the connection capture the execution plan of read queries
+// slower than threshold, logging it through the slow log. Plans are sampled at most
+// once per query template per minute.
+func WithAutoExplain(threshold time.Duration) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.autoExplain = &autoExplain{
+			threshold: threshold,
+			interval:  defaultExplainInterval,
+			lastRuns:  make(map[string]time.Duration),
+		}
+	}
+}
+
+func (ae *autoExplain) shouldExplain(q string, duration time.Duration) bool {
+	if duration < ae.threshold {
+		return false
+	}
+
+	ae.lock.Lock()
+	defer ae.lock.Unlock()
+
+	now := timex.Now()
+	if last, ok := ae.lastRuns[q]; ok && now-last < ae.interval {
+		return false
+	}
+
+	ae.lastRuns[q] = now
+	return true
+}
+
+func (db *commonSqlConn) maybeExplain(q string, args []interface{}, duration time.Duration) {
+	if db.autoExplain == nil || !db.isReadStatement(q) {
+		return
+	}
+	if !db.autoExplain.shouldExplain(q, duration) {
+		return
+	}
+
+	threading.GoSafe(func() {
+		db.explain(q, args)
+	})
+}
+
+func (db *commonSqlConn) explain(q string, args []interface{}) {
+	datasource, err := db.DataSourceResp(q)
+	if err != nil {
+		return
+	}
+
+	conn, err := db.connProv(datasource)
+	if err != nil {
+		return
+	}
+
+	prefix := "EXPLAIN "
+	if db.driverName == postgresDriverName {
+		prefix = "EXPLAIN ANALYZE "
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), db.autoExplain.threshold*2+time.Second)
+	defer cancel()
+
+	rows, err := conn.QueryContext(ctx, prefix+q, args...)
+	if err != nil {
+		logx.Errorf("explain failed for slow query - %s, error: %v", q, err)
+		return
+	}
+	defer rows.Close()
+
+	plan, err := readExplainPlan(rows)
+	if err != nil {
+		logx.Errorf("explain failed for slow query - %s, error: %v", q, err)
+		return
+	}
+
+	logx.Slowf("[SQL] slow query plan - %s\n%s", q, plan)
+}
+
+func readExplainPlan(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		holders := make([]sql.NullString, len(columns))
+		for i := range values {
+			values[i] = &holders[i]
+		}
+		if err := rows.Scan(values...); err != nil {
+			return "", err
+		}
+
+		fields := make([]string, len(holders))
+		for i, holder := range holders {
+			fields[i] = holder.String
+		}
+		lines = append(lines, strings.Join(fields, "\t"))
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
@@ -0,0 +1,23 @@
+package sqlx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoExplainSampling(t *testing.T) {
+	ae := &autoExplain{
+		threshold: time.Millisecond * 100,
+		interval:  time.Minute,
+		lastRuns:  make(map[string]time.Duration),
+	}
+
+	assert.False(t, ae.shouldExplain("select 1 from t", time.Millisecond*50))
+	assert.True(t, ae.shouldExplain("select 1 from t", time.Millisecond*200))
+	// sampled out within the interval
+	assert.False(t, ae.shouldExplain("select 1 from t", time.Millisecond*200))
+	// another query template has its own sample slot
+	assert.True(t, ae.shouldExplain("select 2 from t", time.Millisecond*200))
+}
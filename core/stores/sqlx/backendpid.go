@@ -0,0 +1,68 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ErrBackendPIDUnsupported is an error that indicates the driver has no known
+// backend id query.
+var ErrBackendPIDUnsupported = errors.New("backend pid is not supported on this driver")
+
+// BackendPID returns the server-side connection id, like BackendPIDCtx.
+func BackendPID(conn SqlConn) (int, error) {
+	return BackendPIDCtx(context.Background(), conn)
+}
+
+// BackendPIDCtx returns the id the server assigned to a master connection,
+// pg_backend_pid() on postgres and CONNECTION_ID() on mysql. DB-side logs
+// reference this id rather than our query text, so it's the correlation handle
+// when diagnosing lock waits. The query runs on a dedicated connection checked
+// out of the pool, and the pid is attached to the current trace span as
+// sql.backend_pid. Note that pooled connections rotate, so sample the pid
+// close to the statements being diagnosed.
+func BackendPIDCtx(ctx context.Context, conn SqlConn) (int, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	var query string
+	switch db.driverName {
+	case mysqlDriverName:
+		query = "SELECT CONNECTION_ID()"
+	case postgresDriverName:
+		query = "SELECT pg_backend_pid()"
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrBackendPIDUnsupported, db.driverName)
+	}
+
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return 0, err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return 0, err
+	}
+
+	c, err := pool.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	var pid int
+	if err := c.QueryRowContext(ctx, query).Scan(&pid); err != nil {
+		return 0, err
+	}
+
+	oteltrace.SpanFromContext(ctx).SetAttributes(backendPIDKey.Int(pid))
+	return pid, nil
+}
@@ -0,0 +1,32 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendPID(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`SELECT CONNECTION_ID\(\)`).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(12345))
+
+		conn := &commonSqlConn{
+			driverName: mysqlDriverName,
+			connProv: func(ds string) (*sql.DB, error) {
+				return db, nil
+			},
+		}
+		pid, err := BackendPID(conn)
+		assert.Nil(t, err)
+		assert.Equal(t, 12345, pid)
+	})
+}
+
+func TestBackendPIDUnsupportedDriver(t *testing.T) {
+	conn := &commonSqlConn{driverName: "sqlite3"}
+	_, err := BackendPID(conn)
+	assert.ErrorIs(t, err, ErrBackendPIDUnsupported)
+}
@@ -0,0 +1,35 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// A BatchStmt is one statement of a batch, with its own args.
+type BatchStmt struct {
+	Query string
+	Args  []interface{}
+}
+
+// ExecBatch executes stmts in order on session, like ExecBatchCtx.
+func ExecBatch(session Session, stmts ...BatchStmt) ([]sql.Result, error) {
+	return ExecBatchCtx(context.Background(), session, stmts...)
+}
+
+// ExecBatchCtx executes stmts in order on session, returning the sql.Result of
+// each statement so callers can assert rows affected per statement. It stops at
+// the first failing statement, returning the results collected so far together
+// with the error. Pass a transaction session to make the batch atomic.
+func ExecBatchCtx(ctx context.Context, session Session, stmts ...BatchStmt) ([]sql.Result, error) {
+	results := make([]sql.Result, 0, len(stmts))
+	for _, stmt := range stmts {
+		result, err := session.ExecCtx(ctx, stmt.Query, stmt.Args...)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
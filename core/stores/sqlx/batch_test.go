@@ -0,0 +1,49 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecBatchCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.TransactCtx(context.Background(), func(ctx context.Context, session Session) error {
+			results, err := ExecBatchCtx(ctx, session,
+				BatchStmt{Query: "insert into users (name) values (?)", Args: []interface{}{"foo"}},
+				BatchStmt{Query: "update users set active = ?", Args: []interface{}{true}})
+			assert.Nil(t, err)
+			assert.Len(t, results, 2)
+			affected, err := results[1].RowsAffected()
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), affected)
+			return nil
+		})
+		assert.Nil(t, err)
+	})
+}
+
+func TestExecBatchCtxStopsOnError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("update users").WillReturnError(errors.New("update failed"))
+
+		conn := NewSqlConnFromDB(db)
+		results, err := ExecBatch(conn,
+			BatchStmt{Query: "insert into users (name) values (?)", Args: []interface{}{"foo"}},
+			BatchStmt{Query: "update users set active = ?", Args: []interface{}{true}},
+			BatchStmt{Query: "delete from users"})
+		assert.NotNil(t, err)
+		assert.Len(t, results, 1)
+	})
+}
@@ -0,0 +1,34 @@
+package sqlx
+
+import "context"
+
+// BuildQuery assembles the final query without executing it, like BuildQueryCtx.
+func BuildQuery(conn SqlConn, query string, args ...interface{}) (
+	string, []interface{}, error) {
+	return BuildQueryCtx(context.Background(), conn, query, args...)
+}
+
+// BuildQueryCtx runs the same preprocessing pipeline as ExecCtx — statement
+// policy, unqualified-write guard, arg validation, then shard key tagging and
+// hint decoration — and returns the query and args that would reach the
+// driver, stopping before routing and execution. Use it to preview generated
+// SQL in debugging tools, or to assert on it in tests without a database.
+func BuildQueryCtx(ctx context.Context, conn SqlConn, query string,
+	args ...interface{}) (string, []interface{}, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := db.checkStatement(query); err != nil {
+		return "", nil, err
+	}
+	if err := db.checkUnqualifiedWrite(ctx, query); err != nil {
+		return "", nil, err
+	}
+	if err := db.validateArgs(query, args); err != nil {
+		return "", nil, err
+	}
+
+	return db.decorateQuery(ctx, query), args, nil
+}
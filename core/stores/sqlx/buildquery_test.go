@@ -0,0 +1,35 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildQuery(t *testing.T) {
+	conn := NewSqlConn("mysql", "any", WithPerQueryHint(
+		func(ctx context.Context, query string) string {
+			return "max_execution_time=1000"
+		}))
+
+	q, args, err := BuildQuery(conn, "SELECT * FROM users WHERE id = ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? /* max_execution_time=1000 */", q)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestBuildQueryValidation(t *testing.T) {
+	conn := NewSqlConn("mysql", "any", WithValidateArgCount(), WithGuardUnqualifiedWrites())
+
+	_, _, err := BuildQuery(conn, "SELECT * FROM users WHERE id = ?")
+	assert.Equal(t, ErrArgCountMismatch{Want: 1, Got: 0}, err)
+
+	_, _, err = BuildQuery(conn, "DELETE FROM users")
+	assert.ErrorIs(t, err, ErrUnqualifiedWrite)
+}
+
+func TestBuildQueryUnsupportedConn(t *testing.T) {
+	_, _, err := BuildQuery(new(mockedConn), "SELECT 1")
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
@@ -44,7 +44,8 @@ func (c *mockedConn) QueryRowsPartialCtx(ctx context.Context, v interface{}, que
 	panic("implement me")
 }
 
-func (c *mockedConn) TransactCtx(ctx context.Context, fn func(context.Context, Session) error) error {
+func (c *mockedConn) TransactCtx(ctx context.Context, fn func(context.Context, Session) error,
+	opts ...TransactOption) error {
 	panic("should not called")
 }
 
@@ -52,6 +53,14 @@ func (c *mockedConn) Exec(query string, args ...interface{}) (sql.Result, error)
 	return c.ExecCtx(context.Background(), query, args...)
 }
 
+func (c *mockedConn) InTransaction() bool {
+	return false
+}
+
+func (c *mockedConn) Ping(_ context.Context) error {
+	return nil
+}
+
 func (c *mockedConn) Prepare(query string) (StmtSession, error) {
 	panic("should not called")
 }
@@ -76,7 +85,7 @@ func (c *mockedConn) RawDB() (*sql.DB, error) {
 	panic("should not called")
 }
 
-func (c *mockedConn) Transact(func(session Session) error) error {
+func (c *mockedConn) Transact(func(session Session) error, ...TransactOption) error {
 	panic("should not called")
 }
 
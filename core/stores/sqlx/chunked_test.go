@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsChunked(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select id, name from users where id in").
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "first").AddRow(2, "second"))
+		mock.ExpectQuery("select id, name from users where id in").
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(3, "third"))
+
+		var users []struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+		assert.Nil(t, QueryRowsChunked(NewSqlConnFromDB(db), &users,
+			"select id, name from users where id in (?)",
+			[]interface{}{1, 2, 3}, 2))
+		assert.Len(t, users, 3)
+		assert.Equal(t, "third", users[2].Name)
+	})
+}
+
+func TestQueryRowsChunkedConcurrent(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.MatchExpectationsInOrder(false)
+		for i := 1; i <= 4; i++ {
+			mock.ExpectQuery("select id from users where id in").
+				WithArgs(i).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i))
+		}
+
+		var ids []struct {
+			ID int `db:"id"`
+		}
+		assert.Nil(t, QueryRowsChunked(NewSqlConnFromDB(db), &ids,
+			"select id from users where id in (?)",
+			[]interface{}{1, 2, 3, 4}, 1, WithConcurrency(2)))
+		assert.Len(t, ids, 4)
+	})
+}
+
+func TestQueryRowsChunkedError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select id from users where id in").
+			WithArgs(1).
+			WillReturnError(errors.New("chunk failed"))
+
+		var ids []struct {
+			ID int `db:"id"`
+		}
+		err := QueryRowsChunked(NewSqlConnFromDB(db), &ids,
+			"select id from users where id in (?)", []interface{}{1, 2}, 1)
+		assert.NotNil(t, err)
+		assert.NotEqual(t, "context canceled", err.Error())
+	})
+}
+
+func TestQueryRowsChunkedValidation(t *testing.T) {
+	conn := &commonSqlConn{}
+	var ids []int
+	assert.Equal(t, ErrInvalidChunkSize, QueryRowsChunked(conn, &ids,
+		"select id from users where id in (?)", []interface{}{1}, 0))
+	assert.Equal(t, ErrUnsupportedValueType, QueryRowsChunked(conn, new(int),
+		"select id from users where id in (?)", []interface{}{1}, 1))
+	assert.Nil(t, QueryRowsChunked(conn, &ids,
+		"select id from users where id in (?)", nil, 1))
+}
@@ -0,0 +1,82 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrStatementNotAllowed is an error that indicates the statement type is
+// rejected by the connection's statement policy.
+var ErrStatementNotAllowed = errors.New("statement type not allowed")
+
+type statementPolicy struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// WithStatementPolicy rejects statements whose classified type (SELECT, INSERT,
+// UPDATE, ...) is in deny, or not in allow when allow is non-empty, before they
+// hit the database. Use it to make a read-only connection enforce itself, e.g.
+// WithStatementPolicy([]string{"SELECT"}, nil).
+func WithStatementPolicy(allow, deny []string) SqlOption {
+	return func(conn *commonSqlConn) {
+		policy := &statementPolicy{
+			allow: make(map[string]bool, len(allow)),
+			deny:  make(map[string]bool, len(deny)),
+		}
+		for _, kind := range allow {
+			policy.allow[strings.ToUpper(kind)] = true
+		}
+		for _, kind := range deny {
+			policy.deny[strings.ToUpper(kind)] = true
+		}
+		conn.policy = policy
+	}
+}
+
+func (db *commonSqlConn) checkStatement(q string) error {
+	if db.policy == nil {
+		return nil
+	}
+
+	kind := db.classify(q)
+	if db.policy.deny[kind] {
+		return fmt.Errorf("%w: %s", ErrStatementNotAllowed, kind)
+	}
+	if len(db.policy.allow) > 0 && !db.policy.allow[kind] {
+		return fmt.Errorf("%w: %s", ErrStatementNotAllowed, kind)
+	}
+
+	return nil
+}
+
+// classifyStatement returns the uppercased leading keyword of a statement,
+// skipping comments and parentheses, or an empty string if there is none.
+func classifyStatement(query string) string {
+	s := strings.TrimSpace(query)
+	for {
+		switch {
+		case strings.HasPrefix(s, "--"):
+			idx := strings.IndexByte(s, '\n')
+			if idx < 0 {
+				return ""
+			}
+			s = strings.TrimSpace(s[idx+1:])
+		case strings.HasPrefix(s, "/*"):
+			idx := strings.Index(s, "*/")
+			if idx < 0 {
+				return ""
+			}
+			s = strings.TrimSpace(s[idx+2:])
+		case strings.HasPrefix(s, "("):
+			s = strings.TrimSpace(s[1:])
+		default:
+			var i int
+			for i < len(s) && (('a' <= s[i] && s[i] <= 'z') || ('A' <= s[i] && s[i] <= 'Z')) {
+				i++
+			}
+			return strings.ToUpper(s[:i])
+		}
+	}
+}
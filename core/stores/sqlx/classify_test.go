@@ -0,0 +1,52 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		query  string
+		expect string
+	}{
+		{"select * from users", "SELECT"},
+		{"  INSERT INTO users VALUES (1)", "INSERT"},
+		{"-- comment\nupdate users set name = ?", "UPDATE"},
+		{"/* hint */ delete from users", "DELETE"},
+		{"(select 1)", "SELECT"},
+		{"", ""},
+		{"-- only a comment", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expect, classifyStatement(test.query))
+	}
+}
+
+func TestWithStatementPolicyAllow(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("kevin"))
+
+		conn := NewSqlConnFromDB(db, WithStatementPolicy([]string{"SELECT"}, nil))
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users limit 1"))
+
+		_, err := conn.ExecCtx(context.Background(), "delete from users")
+		assert.True(t, errors.Is(err, ErrStatementNotAllowed))
+	})
+}
+
+func TestWithStatementPolicyDeny(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := NewSqlConnFromDB(db, WithStatementPolicy(nil, []string{"delete"}))
+		_, err := conn.ExecCtx(context.Background(), "DELETE FROM users")
+		assert.True(t, errors.Is(err, ErrStatementNotAllowed))
+	})
+}
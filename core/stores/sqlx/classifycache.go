@@ -0,0 +1,75 @@
+package sqlx
+
+import (
+	"container/list"
+	"sync"
+)
+
+type (
+	classifyCache struct {
+		size  int
+		lock  sync.Mutex
+		kinds map[string]*list.Element
+		order *list.List
+	}
+
+	classifyEntry struct {
+		query string
+		kind  string
+	}
+)
+
+// WithClassifyCache caches statement classification in a per-connection LRU of
+// the given size, keyed by the query string, so hot queries skip re-parsing on
+// every call. Only useful for workloads that reuse parameterized queries, a
+// size at or below zero disables the cache.
+func WithClassifyCache(size int) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.classifyCache = newClassifyCache(size)
+	}
+}
+
+func newClassifyCache(size int) *classifyCache {
+	if size <= 0 {
+		return nil
+	}
+
+	return &classifyCache{
+		size:  size,
+		kinds: make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+func (c *classifyCache) classify(query string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.kinds[query]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(classifyEntry).kind
+	}
+
+	kind := classifyStatement(query)
+	c.kinds[query] = c.order.PushFront(classifyEntry{
+		query: query,
+		kind:  kind,
+	})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.kinds, oldest.Value.(classifyEntry).query)
+	}
+
+	return kind
+}
+
+// classify returns the classified type of q, consulting the connection's
+// classification cache when one is configured.
+func (db *commonSqlConn) classify(q string) string {
+	if db.classifyCache == nil {
+		return classifyStatement(q)
+	}
+
+	return db.classifyCache.classify(q)
+}
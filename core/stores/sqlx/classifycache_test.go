@@ -0,0 +1,38 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyCache(t *testing.T) {
+	cache := newClassifyCache(2)
+	assert.Equal(t, "SELECT", cache.classify("select 1"))
+	// a second lookup is served from the cache
+	assert.Equal(t, "SELECT", cache.classify("select 1"))
+	assert.Equal(t, 1, cache.order.Len())
+
+	assert.Equal(t, "UPDATE", cache.classify("update users set age = 1"))
+	// touching the oldest entry protects it from the next eviction
+	cache.classify("select 1")
+	assert.Equal(t, "DELETE", cache.classify("delete from users where id = 1"))
+	assert.Equal(t, 2, cache.order.Len())
+	_, ok := cache.kinds["update users set age = 1"]
+	assert.False(t, ok)
+	_, ok = cache.kinds["select 1"]
+	assert.True(t, ok)
+}
+
+func TestWithClassifyCache(t *testing.T) {
+	conn, err := asCommonSqlConn(NewSqlConn("mysql", "any", WithClassifyCache(8)))
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT", conn.classify("select 1"))
+	assert.Equal(t, 1, conn.classifyCache.order.Len())
+
+	// a non-positive size disables the cache
+	conn, err = asCommonSqlConn(NewSqlConn("mysql", "any", WithClassifyCache(0)))
+	assert.Nil(t, err)
+	assert.Nil(t, conn.classifyCache)
+	assert.Equal(t, "SELECT", conn.classify("select 1"))
+}
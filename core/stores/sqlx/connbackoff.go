@@ -0,0 +1,68 @@
+package sqlx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	minConnBackoff = time.Millisecond * 100
+	maxConnBackoff = time.Second * 10
+)
+
+// ErrConnBackoff is an error that indicates pool creation for the datasource
+// recently failed and new attempts are suppressed until the backoff elapses.
+var ErrConnBackoff = errors.New("connection attempts suppressed by backoff")
+
+// connBackoffs tracks datasources whose pool creation failed, so that while
+// one goroutine retries (creation is singleflighted by connManager), the rest
+// fail fast instead of piling up connection attempts on a struggling database.
+var connBackoffs = struct {
+	sync.Mutex
+	servers map[string]*connBackoff
+}{
+	servers: make(map[string]*connBackoff),
+}
+
+type connBackoff struct {
+	failures int
+	until    time.Time
+}
+
+func acquireConnAttempt(server string) error {
+	connBackoffs.Lock()
+	defer connBackoffs.Unlock()
+
+	if b, ok := connBackoffs.servers[server]; ok && time.Now().Before(b.until) {
+		return ErrConnBackoff
+	}
+
+	return nil
+}
+
+func markConnFailure(server string) {
+	connBackoffs.Lock()
+	defer connBackoffs.Unlock()
+
+	b, ok := connBackoffs.servers[server]
+	if !ok {
+		b = &connBackoff{}
+		connBackoffs.servers[server] = b
+	}
+
+	backoff := minConnBackoff << b.failures
+	if backoff > maxConnBackoff || backoff <= 0 {
+		backoff = maxConnBackoff
+	} else {
+		b.failures++
+	}
+	b.until = time.Now().Add(backoff)
+}
+
+func markConnSuccess(server string) {
+	connBackoffs.Lock()
+	defer connBackoffs.Unlock()
+
+	delete(connBackoffs.servers, server)
+}
@@ -0,0 +1,54 @@
+package sqlx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnBackoffFailFast(t *testing.T) {
+	const server = "backoff://fail-fast"
+	defer markConnSuccess(server)
+
+	assert.Nil(t, acquireConnAttempt(server))
+	markConnFailure(server)
+	assert.Equal(t, ErrConnBackoff, acquireConnAttempt(server))
+
+	markConnSuccess(server)
+	assert.Nil(t, acquireConnAttempt(server))
+}
+
+func TestConnBackoffGrowsAndCaps(t *testing.T) {
+	const server = "backoff://grows"
+	defer markConnSuccess(server)
+
+	var last time.Duration
+	for i := 0; i < 10; i++ {
+		markConnFailure(server)
+
+		connBackoffs.Lock()
+		backoff := time.Until(connBackoffs.servers[server].until)
+		// let the next markConnFailure through without waiting out the backoff
+		connBackoffs.servers[server].until = time.Now()
+		connBackoffs.Unlock()
+
+		assert.True(t, backoff > last-minConnBackoff)
+		assert.True(t, backoff <= maxConnBackoff)
+		last = backoff
+	}
+
+	assert.True(t, last > maxConnBackoff-time.Second)
+}
+
+func TestGetSqlConnBacksOff(t *testing.T) {
+	const server = "backoff://unknown-driver"
+	defer markConnSuccess(server)
+
+	_, err := getSqlConn("no-such-driver", server)
+	assert.NotNil(t, err)
+	assert.NotEqual(t, ErrConnBackoff, err)
+
+	_, err = getSqlConn("no-such-driver", server)
+	assert.Equal(t, ErrConnBackoff, err)
+}
@@ -0,0 +1,76 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+)
+
+// ErrConnNotExecer is an error that indicates the driver connection doesn't
+// support executing statements directly.
+var ErrConnNotExecer = errors.New("driver connection doesn't implement ExecerContext")
+
+// A ConnectHook runs on every new physical connection before it joins the
+// pool, for session state that must be set per connection, like timezone,
+// sql_mode or search_path. Returning an error discards the connection.
+type ConnectHook func(ctx context.Context, conn driver.Conn) error
+
+// WithConnectHook runs hook on every new physical connection of the pool.
+// The pool is cached per datasource, so the hook of the first conn opening
+// a datasource applies to it.
+func WithConnectHook(hook ConnectHook) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.connectHook = hook
+	}
+}
+
+// ExecOnConn executes query on a raw driver connection, for use in a
+// ConnectHook, e.g. ExecOnConn(ctx, conn, "SET time_zone = '+00:00'").
+func ExecOnConn(ctx context.Context, conn driver.Conn, query string,
+	args ...driver.NamedValue) error {
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		return ErrConnNotExecer
+	}
+
+	_, err := execer.ExecContext(ctx, query, args)
+	return err
+}
+
+// hookConnector wraps a driver.Connector, running the hook on each new connection.
+type hookConnector struct {
+	base driver.Connector
+	hook ConnectHook
+}
+
+func (c hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.hook(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c hookConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// dsnConnector adapts drivers without driver.DriverContext to a connector.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
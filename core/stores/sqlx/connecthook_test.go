@@ -0,0 +1,109 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	hookDriverOnce sync.Once
+	hookExecLock   sync.Mutex
+	hookExecs      []string
+)
+
+type hookTestDriver struct{}
+
+func (d hookTestDriver) Open(_ string) (driver.Conn, error) {
+	return hookTestConn{}, nil
+}
+
+type hookTestConn struct{}
+
+func (c hookTestConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c hookTestConn) Close() error {
+	return nil
+}
+
+func (c hookTestConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c hookTestConn) ExecContext(_ context.Context, query string,
+	_ []driver.NamedValue) (driver.Result, error) {
+	hookExecLock.Lock()
+	defer hookExecLock.Unlock()
+	hookExecs = append(hookExecs, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c hookTestConn) Ping(_ context.Context) error {
+	return nil
+}
+
+func registerHookTestDriver() {
+	hookDriverOnce.Do(func() {
+		sql.Register("hooktest", hookTestDriver{})
+	})
+}
+
+func TestWithConnectHook(t *testing.T) {
+	registerHookTestDriver()
+
+	var hooked bool
+	conn := NewSqlConn("hooktest", "hooktest://with-hook", WithConnectHook(
+		func(ctx context.Context, conn driver.Conn) error {
+			hooked = true
+			return ExecOnConn(ctx, conn, "SET time_zone = '+00:00'")
+		}))
+	c, err := asCommonSqlConn(conn)
+	assert.Nil(t, err)
+	db, err := c.connProv("")
+	assert.Nil(t, err)
+	assert.Nil(t, db.Ping())
+	assert.True(t, hooked)
+
+	hookExecLock.Lock()
+	defer hookExecLock.Unlock()
+	assert.Contains(t, hookExecs, "SET time_zone = '+00:00'")
+}
+
+func TestWithConnectHookError(t *testing.T) {
+	registerHookTestDriver()
+
+	conn := NewSqlConn("hooktest", "hooktest://hook-error", WithConnectHook(
+		func(_ context.Context, _ driver.Conn) error {
+			return errors.New("init failed")
+		}))
+	c, err := asCommonSqlConn(conn)
+	assert.Nil(t, err)
+	_, err = c.connProv("")
+	assert.NotNil(t, err)
+}
+
+func TestExecOnConnNotExecer(t *testing.T) {
+	err := ExecOnConn(context.Background(), nonExecerConn{}, "SELECT 1")
+	assert.Equal(t, ErrConnNotExecer, err)
+}
+
+type nonExecerConn struct{}
+
+func (c nonExecerConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c nonExecerConn) Close() error {
+	return nil
+}
+
+func (c nonExecerConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,171 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WithConn grabs one physical connection from the master pool, runs fn with a
+// Session pinned to it, and releases the connection afterwards. Use it for
+// session-scoped features like temp tables, SET variables and advisory locks,
+// where the pool doesn't guarantee statement affinity outside a transaction.
+func WithConn(ctx context.Context, conn SqlConn, fn func(context.Context, Session) error) error {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return err
+	}
+
+	raw, err := pool.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer raw.Close()
+
+	return fn(ctx, connSession{raw: rawConn{conn: raw}})
+}
+
+type (
+	// connSession is a Session pinned to one physical connection.
+	connSession struct {
+		raw rawConn
+	}
+
+	// rawConn adapts *sql.Conn to the sessionConn interface.
+	rawConn struct {
+		conn *sql.Conn
+	}
+)
+
+func (r rawConn) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return r.conn.ExecContext(context.Background(), q, args...)
+}
+
+func (r rawConn) ExecContext(ctx context.Context, q string, args ...interface{}) (
+	sql.Result, error) {
+	return r.conn.ExecContext(ctx, q, args...)
+}
+
+func (r rawConn) Query(q string, args ...interface{}) (*sql.Rows, error) {
+	return r.conn.QueryContext(context.Background(), q, args...)
+}
+
+func (r rawConn) QueryContext(ctx context.Context, q string, args ...interface{}) (
+	*sql.Rows, error) {
+	return r.conn.QueryContext(ctx, q, args...)
+}
+
+func (s connSession) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return s.ExecCtx(context.Background(), q, args...)
+}
+
+func (s connSession) ExecCtx(ctx context.Context, q string, args ...interface{}) (
+	result sql.Result, err error) {
+	ctx, span := startSpan(ctx, "Exec")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return exec(ctx, s.raw, q, args...)
+}
+
+func (s connSession) InTransaction() bool {
+	return false
+}
+
+func (s connSession) Prepare(q string) (StmtSession, error) {
+	return s.PrepareCtx(context.Background(), q)
+}
+
+func (s connSession) PrepareCtx(ctx context.Context, q string) (stmtSession StmtSession, err error) {
+	ctx, span := startSpan(ctx, "Prepare")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	stmt, err := s.raw.conn.PrepareContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	countStmtPrepared()
+	return statement{
+		query: q,
+		stmt:  stmt,
+	}, nil
+}
+
+func (s connSession) QueryRow(v interface{}, q string, args ...interface{}) error {
+	return s.QueryRowCtx(context.Background(), v, q, args...)
+}
+
+func (s connSession) QueryRowCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, "QueryRow")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return query(ctx, s.raw, func(rows *sql.Rows) error {
+		return unmarshalRow(v, rows, true)
+	}, q, args...)
+}
+
+func (s connSession) QueryRowPartial(v interface{}, q string, args ...interface{}) error {
+	return s.QueryRowPartialCtx(context.Background(), v, q, args...)
+}
+
+func (s connSession) QueryRowPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, "QueryRowPartial")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return query(ctx, s.raw, func(rows *sql.Rows) error {
+		return unmarshalRow(v, rows, false)
+	}, q, args...)
+}
+
+func (s connSession) QueryRows(v interface{}, q string, args ...interface{}) error {
+	return s.QueryRowsCtx(context.Background(), v, q, args...)
+}
+
+func (s connSession) QueryRowsCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, "QueryRows")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return query(ctx, s.raw, func(rows *sql.Rows) error {
+		return unmarshalRows(v, rows, true)
+	}, q, args...)
+}
+
+func (s connSession) QueryRowsPartial(v interface{}, q string, args ...interface{}) error {
+	return s.QueryRowsPartialCtx(context.Background(), v, q, args...)
+}
+
+func (s connSession) QueryRowsPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) (err error) {
+	ctx, span := startSpan(ctx, "QueryRowsPartial")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return query(ctx, s.raw, func(rows *sql.Rows) error {
+		return unmarshalRows(v, rows, false)
+	}, q, args...)
+}
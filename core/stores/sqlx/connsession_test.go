@@ -0,0 +1,41 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithConn(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("SET @user_id").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("select (.+)").WillReturnRows(
+			sqlmock.NewRows([]string{"@user_id"}).FromCSVString("42"))
+
+		conn := NewSqlConnFromDB(db)
+		err := WithConn(context.Background(), conn, func(ctx context.Context, session Session) error {
+			if _, err := session.ExecCtx(ctx, "SET @user_id = ?", 42); err != nil {
+				return err
+			}
+
+			var id int64
+			if err := session.QueryRowCtx(ctx, &id, "select @user_id"); err != nil {
+				return err
+			}
+
+			assert.Equal(t, int64(42), id)
+			return nil
+		})
+		assert.Nil(t, err)
+	})
+}
+
+func TestWithConnUnsupportedConn(t *testing.T) {
+	err := WithConn(context.Background(), new(mockedConn), func(context.Context, Session) error {
+		return nil
+	})
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
@@ -0,0 +1,76 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// consistencyWaitSeconds bounds how long a slave may be waited on to apply the
+// token before the read falls back to master.
+const consistencyWaitSeconds = 1
+
+type consistencyTokenKey struct{}
+
+// WithConsistencyToken returns a context carrying a causal consistency token,
+// a GTID set on mysql or an LSN on postgres, typically captured after a write
+// in another service. Before a read on that context is routed to a slave, the
+// slave is checked to have applied up to the token, waiting briefly on mysql,
+// and the read falls back to master when it hasn't. That gives
+// read-your-writes across service boundaries without pinning every read to
+// master.
+func WithConsistencyToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, consistencyTokenKey{}, token)
+}
+
+func consistencyTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(consistencyTokenKey{}).(string)
+	return token, ok
+}
+
+// resolveConsistentDatasource reroutes a slave-bound read to master when the
+// context carries a consistency token the slave hasn't applied yet.
+func (db *commonSqlConn) resolveConsistentDatasource(ctx context.Context,
+	datasource string) string {
+	token, ok := consistencyTokenFromContext(ctx)
+	if !ok || db.roleOf(datasource) != "slave" {
+		return datasource
+	}
+
+	if db.slaveAppliedToken(ctx, datasource, token) {
+		return datasource
+	}
+
+	if master, ok := db.datasource["master"]; ok {
+		return master
+	}
+
+	return datasource
+}
+
+func (db *commonSqlConn) slaveAppliedToken(ctx context.Context, datasource, token string) bool {
+	conn, err := db.connProv(datasource)
+	if err != nil {
+		return false
+	}
+
+	switch db.driverName {
+	case mysqlDriverName:
+		// 0 means the GTID set was applied within the wait, NULL or >0 means not
+		var waited sql.NullInt64
+		row := conn.QueryRowContext(ctx, "SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)",
+			token, consistencyWaitSeconds)
+		if err := row.Scan(&waited); err != nil {
+			return false
+		}
+		return waited.Valid && waited.Int64 == 0
+	case postgresDriverName:
+		var applied sql.NullBool
+		row := conn.QueryRowContext(ctx, "SELECT pg_last_wal_replay_lsn() >= $1::pg_lsn", token)
+		if err := row.Scan(&applied); err != nil {
+			return false
+		}
+		return applied.Valid && applied.Bool
+	default:
+		return false
+	}
+}
@@ -0,0 +1,67 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func consistencyTestConn(slaveDB *sql.DB) *commonSqlConn {
+	return &commonSqlConn{
+		driverName: mysqlDriverName,
+		datasource: map[string]string{
+			"master": "master-dsn",
+			"slave":  "slave-dsn",
+		},
+		cluster:     true,
+		clusterMode: true,
+		connProv: func(ds string) (*sql.DB, error) {
+			if ds == "slave-dsn" {
+				return slaveDB, nil
+			}
+			return nil, errors.New("unexpected datasource " + ds)
+		},
+		brk: breaker.NewBreaker(),
+	}
+}
+
+func TestResolveConsistentDatasourceCaughtUp(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`SELECT WAIT_FOR_EXECUTED_GTID_SET\(\?, \?\)`).
+			WithArgs("gtid:1-5", consistencyWaitSeconds).
+			WillReturnRows(sqlmock.NewRows([]string{"waited"}).AddRow(0))
+
+		conn := consistencyTestConn(db)
+		ctx := WithConsistencyToken(context.Background(), "gtid:1-5")
+		assert.Equal(t, "slave-dsn", conn.resolveConsistentDatasource(ctx, "slave-dsn"))
+	})
+}
+
+func TestResolveConsistentDatasourceBehind(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`SELECT WAIT_FOR_EXECUTED_GTID_SET\(\?, \?\)`).
+			WithArgs("gtid:1-5", consistencyWaitSeconds).
+			WillReturnRows(sqlmock.NewRows([]string{"waited"}).AddRow(nil))
+
+		conn := consistencyTestConn(db)
+		ctx := WithConsistencyToken(context.Background(), "gtid:1-5")
+		assert.Equal(t, "master-dsn", conn.resolveConsistentDatasource(ctx, "slave-dsn"))
+	})
+}
+
+func TestResolveConsistentDatasourceNoToken(t *testing.T) {
+	conn := consistencyTestConn(nil)
+	assert.Equal(t, "slave-dsn",
+		conn.resolveConsistentDatasource(context.Background(), "slave-dsn"))
+}
+
+func TestResolveConsistentDatasourceMasterUntouched(t *testing.T) {
+	conn := consistencyTestConn(nil)
+	ctx := WithConsistencyToken(context.Background(), "gtid:1-5")
+	assert.Equal(t, "master-dsn", conn.resolveConsistentDatasource(ctx, "master-dsn"))
+}
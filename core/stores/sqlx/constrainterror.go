@@ -0,0 +1,153 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// ConstraintType identifies the kind of constraint that was violated.
+type ConstraintType int
+
+// The constraint types that can be violated.
+const (
+	ConstraintUnique ConstraintType = iota
+	ConstraintForeignKey
+	ConstraintNotNull
+	ConstraintCheck
+)
+
+// mysql constraint violation error numbers
+const (
+	mysqlErrDupEntry        = 1062
+	mysqlErrNoReferencedRow = 1452
+	mysqlErrRowIsReferenced = 1451
+	mysqlErrBadNull         = 1048
+	mysqlErrCheckViolated   = 3819
+)
+
+// postgres constraint violation error codes, class 23
+const (
+	pgErrUnique     = "23505"
+	pgErrForeignKey = "23503"
+	pgErrNotNull    = "23502"
+	pgErrCheck      = "23514"
+)
+
+var (
+	mysqlKeyRegex        = regexp.MustCompile(`for key '([^']+)'`)
+	mysqlConstraintRegex = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	mysqlColumnRegex     = regexp.MustCompile(`Column '([^']+)'`)
+	mysqlCheckRegex      = regexp.MustCompile(`Check constraint '([^']+)'`)
+)
+
+// A ConstraintError is a driver-independent constraint violation, so callers
+// can map violations to responses without string-matching driver error text.
+type ConstraintError struct {
+	Type       ConstraintType
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("constraint violation on %q: %s", e.Constraint, e.Err.Error())
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+func (t ConstraintType) String() string {
+	switch t {
+	case ConstraintUnique:
+		return "unique"
+	case ConstraintForeignKey:
+		return "foreign key"
+	case ConstraintNotNull:
+		return "not null"
+	case ConstraintCheck:
+		return "check"
+	default:
+		return "unknown"
+	}
+}
+
+// wrapConstraintError wraps MySQL and Postgres constraint violations as
+// *ConstraintError, returning other errors unchanged.
+func wrapConstraintError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDupEntry:
+			return &ConstraintError{
+				Type:       ConstraintUnique,
+				Constraint: firstMatch(mysqlKeyRegex, mysqlErr.Message),
+				Err:        err,
+			}
+		case mysqlErrNoReferencedRow, mysqlErrRowIsReferenced:
+			return &ConstraintError{
+				Type:       ConstraintForeignKey,
+				Constraint: firstMatch(mysqlConstraintRegex, mysqlErr.Message),
+				Err:        err,
+			}
+		case mysqlErrBadNull:
+			return &ConstraintError{
+				Type:       ConstraintNotNull,
+				Constraint: firstMatch(mysqlColumnRegex, mysqlErr.Message),
+				Err:        err,
+			}
+		case mysqlErrCheckViolated:
+			return &ConstraintError{
+				Type:       ConstraintCheck,
+				Constraint: firstMatch(mysqlCheckRegex, mysqlErr.Message),
+				Err:        err,
+			}
+		}
+		return err
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		var constraintType ConstraintType
+		switch pqErr.Code {
+		case pgErrUnique:
+			constraintType = ConstraintUnique
+		case pgErrForeignKey:
+			constraintType = ConstraintForeignKey
+		case pgErrNotNull:
+			constraintType = ConstraintNotNull
+		case pgErrCheck:
+			constraintType = ConstraintCheck
+		default:
+			return err
+		}
+
+		constraint := pqErr.Constraint
+		if len(constraint) == 0 {
+			constraint = pqErr.Column
+		}
+		return &ConstraintError{
+			Type:       constraintType,
+			Constraint: constraint,
+			Err:        err,
+		}
+	}
+
+	return err
+}
+
+func firstMatch(re *regexp.Regexp, message string) string {
+	matches := re.FindStringSubmatch(message)
+	if len(matches) < 2 {
+		return ""
+	}
+
+	return matches[1]
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapConstraintErrorMysql(t *testing.T) {
+	err := wrapConstraintError(&mysql.MySQLError{
+		Number:  mysqlErrDupEntry,
+		Message: "Duplicate entry 'kevin' for key 'users.name_uidx'",
+	})
+
+	var ce *ConstraintError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, ConstraintUnique, ce.Type)
+	assert.Equal(t, "users.name_uidx", ce.Constraint)
+
+	err = wrapConstraintError(&mysql.MySQLError{
+		Number:  mysqlErrNoReferencedRow,
+		Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))",
+	})
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, ConstraintForeignKey, ce.Type)
+	assert.Equal(t, "fk_user", ce.Constraint)
+}
+
+func TestWrapConstraintErrorPostgres(t *testing.T) {
+	err := wrapConstraintError(&pq.Error{
+		Code:       pgErrUnique,
+		Constraint: "users_name_key",
+	})
+
+	var ce *ConstraintError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, ConstraintUnique, ce.Type)
+	assert.Equal(t, "users_name_key", ce.Constraint)
+	assert.Equal(t, "unique", ce.Type.String())
+}
+
+func TestWrapConstraintErrorPassThrough(t *testing.T) {
+	assert.Nil(t, wrapConstraintError(nil))
+
+	plain := errors.New("plain")
+	assert.Equal(t, plain, wrapConstraintError(plain))
+
+	other := &mysql.MySQLError{Number: 1040, Message: "Too many connections"}
+	assert.Equal(t, error(other), wrapConstraintError(other))
+}
@@ -0,0 +1,37 @@
+package sqlx
+
+import "context"
+
+// Count counts the rows of table matching where, like CountCtx.
+func Count(conn SqlConn, table, where string, args ...interface{}) (int64, error) {
+	return CountCtx(context.Background(), conn, table, where, args...)
+}
+
+// CountCtx builds `SELECT COUNT(*) FROM table WHERE ...` and returns the
+// count, saving the scalar-into-struct dance for the most common aggregate.
+// An empty where counts the whole table. As a SELECT, the query goes through
+// the normal read routing, so it runs on the slave when read/write split is on.
+func CountCtx(ctx context.Context, conn SqlConn, table, where string,
+	args ...interface{}) (int64, error) {
+	query := "SELECT COUNT(*) FROM " + table
+	if len(where) > 0 {
+		query += " WHERE " + where
+	}
+
+	return CountQueryCtx(ctx, conn, query, args...)
+}
+
+// CountQuery runs an arbitrary count query, like CountQueryCtx.
+func CountQuery(conn SqlConn, query string, args ...interface{}) (int64, error) {
+	return CountQueryCtx(context.Background(), conn, query, args...)
+}
+
+// CountQueryCtx runs query, which must return a single integer column, and
+// returns its value. Use it for counts that CountCtx can't express, like
+// joins or DISTINCT.
+func CountQueryCtx(ctx context.Context, conn SqlConn, query string,
+	args ...interface{}) (int64, error) {
+	var count int64
+	err := conn.QueryRowCtx(ctx, &count, query, args...)
+	return count, err
+}
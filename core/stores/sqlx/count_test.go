@@ -0,0 +1,40 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE age > \?`).
+			WithArgs(18).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(100))
+
+		conn := NewSqlConnFromDB(db)
+		count, err := Count(conn, "users", "age > ?", 18)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(42), count)
+
+		count, err = Count(conn, "users", "")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(100), count)
+	})
+}
+
+func TestCountQuery(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`SELECT COUNT\(DISTINCT user_id\) FROM orders`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+		count, err := CountQuery(NewSqlConnFromDB(db),
+			"SELECT COUNT(DISTINCT user_id) FROM orders")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(7), count)
+	})
+}
@@ -0,0 +1,106 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// A Cursor is an open result set consumed one row at a time, holding a
+// physical connection until Close.
+type Cursor struct {
+	conn    *sql.Conn
+	rows    *sql.Rows
+	columns []string
+	opts    scanOptions
+}
+
+// OpenCursor opens a cursor over query, like OpenCursorCtx.
+func OpenCursor(conn SqlConn, query string, args ...interface{}) (*Cursor, error) {
+	return OpenCursorCtx(context.Background(), conn, query, args...)
+}
+
+// OpenCursorCtx runs query on a connection pinned from the master pool, like
+// WithConn does, and returns a Cursor over its result set for incremental
+// consumption, e.g. feeding a huge table through a paginated API without
+// re-running the query per page. The physical connection stays held until
+// Close and counts against the pool's limits the whole time — always Close
+// the cursor, and don't keep one open across long idle periods or the server
+// may reap the connection under it.
+func OpenCursorCtx(ctx context.Context, conn SqlConn, q string,
+	args ...interface{}) (*Cursor, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return nil, err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return nil, err
+	}
+
+	raw, err := pool.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := raw.QueryContext(ctx, q, args...)
+	if err != nil {
+		raw.Close()
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		raw.Close()
+		return nil, err
+	}
+
+	return &Cursor{
+		conn:    raw,
+		rows:    rows,
+		columns: columns,
+		opts:    db.scanOptions(ctx, false),
+	}, nil
+}
+
+// Next scans the next row into v, reporting false when the result set is
+// exhausted. v must be a pointer to a struct or a basic type, as in QueryRow.
+func (c *Cursor) Next(v interface{}) (bool, error) {
+	rv := reflect.ValueOf(v)
+	if err := mapping.ValidatePtr(&rv); err != nil {
+		return false, err
+	}
+
+	if !c.rows.Next() {
+		return false, c.rows.Err()
+	}
+
+	value, err := scanRowValue(c.rows, mapping.Deref(reflect.TypeOf(v)), c.columns, c.opts)
+	if err != nil {
+		return false, err
+	}
+
+	rv.Elem().Set(reflect.Indirect(value))
+	return true, nil
+}
+
+// Close releases the result set and the pinned connection.
+func (c *Cursor) Close() error {
+	rowsErr := c.rows.Close()
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+
+	return rowsErr
+}
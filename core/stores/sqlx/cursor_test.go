@@ -0,0 +1,64 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "first").
+			AddRow(2, "second")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		type user struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		cursor, err := OpenCursor(NewSqlConnFromDB(db), "select id, name from users")
+		assert.Nil(t, err)
+
+		var users []user
+		for {
+			var u user
+			ok, err := cursor.Next(&u)
+			assert.Nil(t, err)
+			if !ok {
+				break
+			}
+			users = append(users, u)
+		}
+		assert.Nil(t, cursor.Close())
+		assert.Equal(t, []user{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}}, users)
+	})
+}
+
+func TestCursorBasicType(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id"}).AddRow(7)
+		mock.ExpectQuery("select id from users").WillReturnRows(rs)
+
+		cursor, err := OpenCursor(NewSqlConnFromDB(db), "select id from users")
+		assert.Nil(t, err)
+		defer cursor.Close()
+
+		var id int64
+		ok, err := cursor.Next(&id)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.EqualValues(t, 7, id)
+
+		ok, err = cursor.Next(&id)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestCursorUnsupportedConn(t *testing.T) {
+	_, err := OpenCursor(new(mockedConn), "select 1")
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
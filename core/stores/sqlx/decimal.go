@@ -0,0 +1,77 @@
+package sqlx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithDecimalAsString scans DECIMAL and NUMERIC columns into string fields
+// straight from the driver's bytes, never round-tripping through float64, so
+// money values keep their exact textual representation. Fields of a decimal
+// type implementing sql.Scanner don't need this, they always get the raw
+// driver value.
+func WithDecimalAsString() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.decimalAsString = true
+	}
+}
+
+// decimalScanner assigns a DECIMAL column to a string destination without a
+// float conversion when the driver hands over the textual form.
+type decimalScanner struct {
+	dest *string
+}
+
+func (s *decimalScanner) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s.dest = ""
+	case []byte:
+		*s.dest = string(v)
+	case string:
+		*s.dest = v
+	case float64:
+		// the driver already parsed it, format without trailing noise
+		*s.dest = strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		*s.dest = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("unable to scan %T into decimal string", src)
+	}
+
+	return nil
+}
+
+// wrapDecimalValues swaps the string destinations of DECIMAL columns for
+// decimalScanners, leaving everything else untouched.
+func wrapDecimalValues(scanner rowsScanner, values []interface{}) {
+	provider, ok := scanner.(columnTypeProvider)
+	if !ok {
+		return
+	}
+
+	types, err := provider.ColumnTypes()
+	if err != nil {
+		return
+	}
+
+	for i, value := range values {
+		if i >= len(types) || !isDecimalType(types[i].DatabaseTypeName()) {
+			continue
+		}
+
+		if dest, ok := value.(*string); ok {
+			values[i] = &decimalScanner{dest: dest}
+		}
+	}
+}
+
+func isDecimalType(dbType string) bool {
+	switch strings.ToUpper(dbType) {
+	case "DECIMAL", "NUMERIC", "MONEY":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// money is a decimal-like type implementing sql.Scanner, keeping the exact
+// textual value the driver returned.
+type money struct {
+	value string
+}
+
+func (m *money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		m.value = string(v)
+	case string:
+		m.value = v
+	default:
+		return fmt.Errorf("unable to scan %T into money", src)
+	}
+	return nil
+}
+
+func TestScanDecimalIntoScanner(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"price"}).AddRow([]byte("19999999999.99"))
+		mock.ExpectQuery("select (.+) from items").WillReturnRows(rows)
+
+		var item struct {
+			Price money `db:"price"`
+		}
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, conn.QueryRow(&item, "select price from items where id = 1"))
+		// no float64 round-trip, the textual value survives untouched
+		assert.Equal(t, "19999999999.99", item.Price.value)
+	})
+}
+
+func TestWithDecimalAsString(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRowsWithColumnDefinition(
+			sqlmock.NewColumn("price").OfType("DECIMAL", []byte("0.30000000000000004")),
+		).AddRow([]byte("0.30000000000000004"))
+		mock.ExpectQuery("select (.+) from items").WillReturnRows(rows)
+
+		var item struct {
+			Price string `db:"price"`
+		}
+		conn := NewSqlConnFromDB(db, WithDecimalAsString())
+		assert.Nil(t, conn.QueryRow(&item, "select price from items where id = 1"))
+		assert.Equal(t, "0.30000000000000004", item.Price)
+	})
+}
+
+func TestDecimalScanner(t *testing.T) {
+	var dest string
+	s := &decimalScanner{dest: &dest}
+	assert.Nil(t, s.Scan([]byte("12.34")))
+	assert.Equal(t, "12.34", dest)
+	assert.Nil(t, s.Scan(float64(12.5)))
+	assert.Equal(t, "12.5", dest)
+	assert.Nil(t, s.Scan(int64(7)))
+	assert.Equal(t, "7", dest)
+	assert.Nil(t, s.Scan(nil))
+	assert.Empty(t, dest)
+	assert.NotNil(t, s.Scan(struct{}{}))
+}
+
+func TestIsDecimalType(t *testing.T) {
+	assert.True(t, isDecimalType("DECIMAL"))
+	assert.True(t, isDecimalType("numeric"))
+	assert.False(t, isDecimalType("VARCHAR"))
+}
@@ -0,0 +1,41 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// A QueryHinter returns the hint text to append to a query, without the
+// comment delimiters, like `use_cache`. An empty return leaves the query
+// unhinted. It's evaluated lazily, once per execution.
+type QueryHinter func(ctx context.Context, query string) string
+
+// WithPerQueryHint appends a hint comment like `/* use_cache */` to every
+// statement, with the text derived per query by hinter, for drivers and
+// proxies that honor caching or plan hints. It shares the decoration
+// pipeline with WithShardKeyTagger, tags are prepended and hints appended.
+func WithPerQueryHint(hinter QueryHinter) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.hinter = hinter
+	}
+}
+
+// decorateQuery runs the query decoration pipeline: the shard key tagger
+// prepends its routing comment, then the hinter appends its hint comment.
+// Statement classification ignores comments, so routing is unaffected.
+func (db *commonSqlConn) decorateQuery(ctx context.Context, q string) string {
+	q = db.tagQuery(ctx, q)
+	if db.hinter == nil {
+		return q
+	}
+
+	hint := db.hinter(ctx, q)
+	if len(hint) == 0 {
+		return q
+	}
+
+	// a `*/` inside the hint would terminate the comment early, strip it
+	hint = strings.ReplaceAll(hint, "*/", "")
+	return fmt.Sprintf("%s /* %s */", q, hint)
+}
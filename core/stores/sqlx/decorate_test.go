@@ -0,0 +1,52 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPerQueryHint(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta("select name from users where id = 1 /* use_cache */")).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+
+		conn := NewSqlConnFromDB(db, WithPerQueryHint(func(_ context.Context, query string) string {
+			if strings.HasPrefix(query, "select") {
+				return "use_cache"
+			}
+			return ""
+		}))
+
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users where id = 1"))
+		assert.Equal(t, "tom", name)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDecorateQueryPipeline(t *testing.T) {
+	conn := &commonSqlConn{
+		shardTagger: func(_ context.Context) string {
+			return "42"
+		},
+		hinter: func(_ context.Context, _ string) string {
+			return "use_cache"
+		},
+	}
+
+	decorated := conn.decorateQuery(context.Background(), "select * from users")
+	assert.Equal(t, "/* shard_key=42 */ select * from users /* use_cache */", decorated)
+	// leading comments don't confuse the classifier
+	assert.True(t, matchReadQuery(decorated))
+}
+
+func TestDecorateQueryNoDecorators(t *testing.T) {
+	conn := &commonSqlConn{}
+	assert.Equal(t, "select 1", conn.decorateQuery(context.Background(), "select 1"))
+}
@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidBatchSize is an error that indicates a non-positive batch size.
+var ErrInvalidBatchSize = errors.New("batch size must be positive")
+
+type (
+	// DeleteBatchOption defines the method to customize DeleteInBatchesCtx.
+	DeleteBatchOption func(*deleteBatchOptions)
+
+	deleteBatchOptions struct {
+		interval time.Duration
+	}
+)
+
+// WithBatchInterval sleeps d between batches, giving replication a chance to
+// catch up during large deletes.
+func WithBatchInterval(d time.Duration) DeleteBatchOption {
+	return func(o *deleteBatchOptions) {
+		o.interval = d
+	}
+}
+
+// DeleteInBatches deletes matching rows in batches, like DeleteInBatchesCtx.
+func DeleteInBatches(conn SqlConn, table, where string, batchSize int,
+	args []interface{}, opts ...DeleteBatchOption) (int64, error) {
+	return DeleteInBatchesCtx(context.Background(), conn, table, where, batchSize, args, opts...)
+}
+
+// DeleteInBatchesCtx repeatedly runs `DELETE FROM table WHERE where LIMIT
+// batchSize` until fewer rows than batchSize are affected, each batch in its
+// own implicit transaction, and returns the total number of rows deleted.
+// Deleting millions of rows this way avoids the long locks and replication lag
+// a single huge DELETE causes. As a write it always runs on the master, and an
+// optional WithBatchInterval sleep between batches throttles the load further.
+func DeleteInBatchesCtx(ctx context.Context, conn SqlConn, table, where string,
+	batchSize int, args []interface{}, opts ...DeleteBatchOption) (int64, error) {
+	if batchSize <= 0 {
+		return 0, ErrInvalidBatchSize
+	}
+
+	var o deleteBatchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT %d", table, where, batchSize)
+	var total int64
+	for {
+		result, err := conn.ExecCtx(ctx, query, args...)
+		if err != nil {
+			return total, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+
+		total += affected
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+
+		if o.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-time.After(o.interval):
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteInBatches(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		pattern := `DELETE FROM logs WHERE created_at < \? LIMIT 100`
+		mock.ExpectExec(pattern).WithArgs("2020-01-01").
+			WillReturnResult(sqlmock.NewResult(0, 100))
+		mock.ExpectExec(pattern).WithArgs("2020-01-01").
+			WillReturnResult(sqlmock.NewResult(0, 100))
+		mock.ExpectExec(pattern).WithArgs("2020-01-01").
+			WillReturnResult(sqlmock.NewResult(0, 37))
+
+		conn := NewSqlConnFromDB(db)
+		total, err := DeleteInBatches(conn, "logs", "created_at < ?", 100,
+			[]interface{}{"2020-01-01"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(237), total)
+	})
+}
+
+func TestDeleteInBatchesWithInterval(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		pattern := `DELETE FROM logs WHERE id > \? LIMIT 10`
+		mock.ExpectExec(pattern).WillReturnResult(sqlmock.NewResult(0, 10))
+		mock.ExpectExec(pattern).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		conn := NewSqlConnFromDB(db)
+		start := time.Now()
+		total, err := DeleteInBatches(conn, "logs", "id > ?", 10,
+			[]interface{}{0}, WithBatchInterval(time.Millisecond*10))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(10), total)
+		assert.True(t, time.Since(start) >= time.Millisecond*10)
+	})
+}
+
+func TestDeleteInBatchesError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		pattern := `DELETE FROM logs WHERE id > \? LIMIT 10`
+		mock.ExpectExec(pattern).WillReturnResult(sqlmock.NewResult(0, 10))
+		mock.ExpectExec(pattern).WillReturnError(errors.New("lock wait timeout"))
+
+		conn := NewSqlConnFromDB(db)
+		total, err := DeleteInBatches(conn, "logs", "id > ?", 10, []interface{}{0})
+		assert.NotNil(t, err)
+		assert.Equal(t, int64(10), total)
+	})
+}
+
+func TestDeleteInBatchesInvalidSize(t *testing.T) {
+	_, err := DeleteInBatches(&commonSqlConn{}, "logs", "id > ?", 0, []interface{}{0})
+	assert.Equal(t, ErrInvalidBatchSize, err)
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// WithAcceptableErrorCodes marks the given driver error codes as acceptable to
+// the breaker, taking MySQL error numbers ("1062") and Postgres SQLSTATE codes
+// ("23505") alike. Use it for expected business errors such as duplicate keys,
+// instead of hand-writing an accept predicate. It extends any previously
+// configured predicate, it never narrows it.
+func WithAcceptableErrorCodes(codes ...string) SqlOption {
+	return func(conn *commonSqlConn) {
+		accepted := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			accepted[code] = true
+		}
+
+		prev := conn.accept
+		conn.accept = func(err error) bool {
+			if prev != nil && prev(err) {
+				return true
+			}
+
+			code, ok := driverErrorCode(err)
+			return ok && accepted[code]
+		}
+	}
+}
+
+// driverErrorCode extracts the driver-specific error code, the MySQL error
+// number or the Postgres SQLSTATE, reporting false for other errors.
+func driverErrorCode(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return strconv.FormatUint(uint64(mysqlErr.Number), 10), true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), true
+	}
+
+	return "", false
+}
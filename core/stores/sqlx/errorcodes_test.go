@@ -0,0 +1,39 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriverErrorCode(t *testing.T) {
+	_, ok := driverErrorCode(nil)
+	assert.False(t, ok)
+	_, ok = driverErrorCode(errors.New("any"))
+	assert.False(t, ok)
+
+	code, ok := driverErrorCode(&mysql.MySQLError{Number: 1062})
+	assert.True(t, ok)
+	assert.Equal(t, "1062", code)
+
+	// wrapped driver errors are still recognized
+	code, ok = driverErrorCode(fmt.Errorf("exec: %w", &pq.Error{Code: "40001"}))
+	assert.True(t, ok)
+	assert.Equal(t, "40001", code)
+}
+
+func TestWithAcceptableErrorCodes(t *testing.T) {
+	conn, err := asCommonSqlConn(NewSqlConn("mysql", "any",
+		WithAcceptableErrorCodes("1062", "40001")))
+	assert.Nil(t, err)
+
+	assert.True(t, conn.acceptable(nil))
+	assert.True(t, conn.acceptable(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, conn.acceptable(&pq.Error{Code: "40001"}))
+	assert.False(t, conn.acceptable(&mysql.MySQLError{Number: 1045}))
+	assert.False(t, conn.acceptable(&pq.Error{Code: "57P01"}))
+}
@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportCSV streams the rows of query to w as CSV, like ExportCSVCtx.
+func ExportCSV(conn SqlConn, w io.Writer, query string, args ...interface{}) (int64, error) {
+	return ExportCSVCtx(context.Background(), conn, w, query, args...)
+}
+
+// ExportCSVCtx streams the rows of query to w as CSV without materializing
+// them, returning the number of data rows written. The first record is a
+// header built from the result columns. NULLs become empty fields, []byte and
+// string values are written as-is, times as RFC 3339; quoting and escaping
+// follow encoding/csv. The export stops with ctx.Err() once ctx is canceled.
+func ExportCSVCtx(ctx context.Context, conn SqlConn, w io.Writer, query string,
+	args ...interface{}) (int64, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.queryRows(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+
+		record := make([]string, len(columns))
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				return err
+			}
+
+			for i, value := range values {
+				record[i] = formatCsvValue(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			count++
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		writer.Flush()
+		return writer.Error()
+	}, query, args...)
+
+	return count, err
+}
+
+func formatCsvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
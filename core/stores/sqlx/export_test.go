@@ -0,0 +1,36 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCSV(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select id, name, note from users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "note"}).
+				AddRow(1, "first", nil).
+				AddRow(2, `say "hi", bye`, "ok"))
+
+		var sb strings.Builder
+		count, err := ExportCSV(NewSqlConnFromDB(db), &sb, "select id, name, note from users")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+		assert.Equal(t, "id,name,note\n1,first,\n2,\"say \"\"hi\"\", bye\",ok\n", sb.String())
+	})
+}
+
+func TestExportCSVCanceled(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var sb strings.Builder
+		_, err := ExportCSVCtx(ctx, NewSqlConnFromDB(db), &sb, "select id from users")
+		assert.Equal(t, context.Canceled, err)
+	})
+}
@@ -0,0 +1,42 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// A QuerySpec pairs a query with its args, for helpers taking several queries.
+type QuerySpec struct {
+	Query string
+	Args  []interface{}
+}
+
+// QueryRowWithFallbackCtx queries one row with primary and, when primary fails,
+// degrades to fallback. ErrNotFound from primary is an empty result, not a
+// failure, so it's returned as is instead of masking it with fallback data.
+func QueryRowWithFallbackCtx(ctx context.Context, conn SqlConn, v interface{},
+	primary, fallback QuerySpec) error {
+	err := conn.QueryRowCtx(ctx, v, primary.Query, primary.Args...)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	logx.WithContext(ctx).Errorf("primary query failed, degrading to fallback: %v", err)
+	return conn.QueryRowCtx(ctx, v, fallback.Query, fallback.Args...)
+}
+
+// QueryRowsWithFallbackCtx queries rows with primary and, when primary fails,
+// degrades to fallback, keeping the degraded-mode logic out of call sites.
+// An empty result from primary is not a failure and doesn't trigger fallback.
+func QueryRowsWithFallbackCtx(ctx context.Context, conn SqlConn, v interface{},
+	primary, fallback QuerySpec) error {
+	err := conn.QueryRowsCtx(ctx, v, primary.Query, primary.Args...)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	logx.WithContext(ctx).Errorf("primary query failed, degrading to fallback: %v", err)
+	return conn.QueryRowsCtx(ctx, v, fallback.Query, fallback.Args...)
+}
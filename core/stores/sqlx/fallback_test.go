@@ -0,0 +1,42 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsWithFallbackCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnError(errors.New("table locked"))
+		mock.ExpectQuery("select (.+) from users_cache").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("cached"))
+
+		var names []string
+		conn := NewSqlConnFromDB(db)
+		err := QueryRowsWithFallbackCtx(context.Background(), conn, &names,
+			QuerySpec{Query: "select name from users"},
+			QuerySpec{Query: "select name from users_cache"})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"cached"}, names)
+	})
+}
+
+func TestQueryRowWithFallbackCtxNotFound(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}))
+
+		var name string
+		conn := NewSqlConnFromDB(db)
+		err := QueryRowWithFallbackCtx(context.Background(), conn, &name,
+			QuerySpec{Query: "select name from users where id = ?", Args: []interface{}{1}},
+			QuerySpec{Query: "select name from users_cache where id = ?", Args: []interface{}{1}})
+		// empty result must not be masked by fallback data
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
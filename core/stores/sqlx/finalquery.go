@@ -0,0 +1,50 @@
+package sqlx
+
+import (
+	"context"
+	"sync"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type finalQueryKey struct{}
+
+// FinalQuery captures the exact SQL string sent to the database for a call,
+// after the decoration pipeline rewrote it. Safe for concurrent use.
+type FinalQuery struct {
+	lock  sync.Mutex
+	query string
+}
+
+// Query returns the captured SQL, empty before the call ran.
+func (f *FinalQuery) Query() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.query
+}
+
+func (f *FinalQuery) set(q string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.query = q
+}
+
+// WithFinalQueryCtx returns a context that records the final rewritten SQL of
+// calls made on it into the returned FinalQuery, for debugging what was
+// actually sent after tagging, hinting and other rewriting.
+func WithFinalQueryCtx(ctx context.Context) (context.Context, *FinalQuery) {
+	capture := new(FinalQuery)
+	return context.WithValue(ctx, finalQueryKey{}, capture), capture
+}
+
+// markFinalQuery records the final SQL into the context capture if one is
+// registered, and stamps the span with the fingerprint of what was actually
+// sent, so observability reflects the rewritten query rather than the
+// caller's template.
+func (db *commonSqlConn) markFinalQuery(ctx context.Context, q string) {
+	if capture, ok := ctx.Value(finalQueryKey{}).(*FinalQuery); ok {
+		capture.set(q)
+	}
+
+	oteltrace.SpanFromContext(ctx).SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
+}
@@ -0,0 +1,41 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFinalQueryCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta("/* shard_key=7 */ select name from users")).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+
+		conn := NewSqlConnFromDB(db, WithShardKeyTagger(func(_ context.Context) string {
+			return "7"
+		}))
+
+		ctx, capture := WithFinalQueryCtx(context.Background())
+		assert.Empty(t, capture.Query())
+
+		var name string
+		assert.Nil(t, conn.QueryRowCtx(ctx, &name, "select name from users where id = 1"))
+		assert.Equal(t, "/* shard_key=7 */ select name from users where id = 1", capture.Query())
+	})
+}
+
+func TestWithFinalQueryCtxExec(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		conn := NewSqlConnFromDB(db)
+		ctx, capture := WithFinalQueryCtx(context.Background())
+		_, err := conn.ExecCtx(ctx, "update users set name = 'jerry' where id = 1")
+		assert.Nil(t, err)
+		assert.Equal(t, "update users set name = 'jerry' where id = 1", capture.Query())
+	})
+}
@@ -0,0 +1,103 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrGroupColumns is an error that indicates the grouped query didn't return
+// one column per key level plus one value column.
+var ErrGroupColumns = errors.New("grouped query must return one column per map level plus a value")
+
+// QueryGrouped runs an aggregate query into nested maps, like QueryGroupedCtx.
+func QueryGrouped(conn SqlConn, m interface{}, query string, args ...interface{}) error {
+	return QueryGroupedCtx(context.Background(), conn, m, query, args...)
+}
+
+// QueryGroupedCtx runs an aggregate query like
+// `SELECT dim1, dim2, SUM(x) FROM ... GROUP BY dim1, dim2` and scans the rows
+// into m, which must be a pointer to a map, possibly nested, such as
+// *map[string]map[string]float64. Columns map onto the map levels left to
+// right, with the last column as value, so the map nesting depth must match
+// the column count minus one. The query goes through the normal read routing.
+func QueryGroupedCtx(ctx context.Context, conn SqlConn, m interface{}, query string,
+	args ...interface{}) error {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	return db.queryRows(ctx, func(rows *sql.Rows) error {
+		return scanGrouped(m, rows)
+	}, query, args...)
+}
+
+func scanGrouped(m interface{}, rows *sql.Rows) error {
+	rv := reflect.ValueOf(m)
+	if err := mapping.ValidatePtr(&rv); err != nil {
+		return err
+	}
+
+	mv := rv.Elem()
+	if mv.Kind() != reflect.Map {
+		return ErrUnsupportedValueType
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != groupDepth(mv.Type())+1 {
+		return ErrGroupColumns
+	}
+
+	if mv.IsNil() {
+		mv.Set(reflect.MakeMap(mv.Type()))
+	}
+
+	for rows.Next() {
+		holders := make([]interface{}, 0, len(columns))
+		target := mv.Type()
+		for i := 0; i < len(columns)-1; i++ {
+			holders = append(holders, reflect.New(target.Key()).Interface())
+			target = target.Elem()
+		}
+		holders = append(holders, reflect.New(target).Interface())
+		if err := rows.Scan(holders...); err != nil {
+			return err
+		}
+
+		// walk down the levels, creating inner maps on first sight of a key
+		level := mv
+		for i := 0; i < len(holders)-2; i++ {
+			key := reflect.ValueOf(holders[i]).Elem()
+			inner := level.MapIndex(key)
+			if !inner.IsValid() {
+				inner = reflect.MakeMap(level.Type().Elem())
+				level.SetMapIndex(key, inner)
+			}
+			level = inner
+		}
+
+		key := reflect.ValueOf(holders[len(holders)-2]).Elem()
+		value := reflect.ValueOf(holders[len(holders)-1]).Elem()
+		level.SetMapIndex(key, value)
+	}
+
+	return rows.Err()
+}
+
+// groupDepth reports the map nesting depth of t, e.g. 2 for map[string]map[string]float64.
+func groupDepth(t reflect.Type) int {
+	var depth int
+	for t.Kind() == reflect.Map {
+		depth++
+		t = t.Elem()
+	}
+
+	return depth
+}
@@ -0,0 +1,60 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryGrouped(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"region", "product", "total"}).
+			AddRow("east", "apple", 10.5).
+			AddRow("east", "banana", 2.25).
+			AddRow("west", "apple", 7)
+		mock.ExpectQuery("select (.+) from sales").WillReturnRows(rows)
+
+		var result map[string]map[string]float64
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, QueryGrouped(conn, &result,
+			"select region, product, sum(amount) from sales group by region, product"))
+		assert.EqualValues(t, map[string]map[string]float64{
+			"east": {"apple": 10.5, "banana": 2.25},
+			"west": {"apple": 7},
+		}, result)
+	})
+}
+
+func TestQueryGroupedFlat(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"region", "total"}).
+			AddRow("east", int64(3)).
+			AddRow("west", int64(5))
+		mock.ExpectQuery("select (.+) from sales").WillReturnRows(rows)
+
+		var result map[string]int64
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, QueryGrouped(conn, &result,
+			"select region, count(*) from sales group by region"))
+		assert.EqualValues(t, map[string]int64{"east": 3, "west": 5}, result)
+	})
+}
+
+func TestQueryGroupedColumnMismatch(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"region", "total"}).AddRow("east", 3)
+		mock.ExpectQuery("select (.+) from sales").WillReturnRows(rows)
+
+		var result map[string]map[string]float64
+		conn := NewSqlConnFromDB(db)
+		assert.Equal(t, ErrGroupColumns, QueryGrouped(conn, &result,
+			"select region, count(*) from sales group by region"))
+	})
+}
+
+func TestQueryGroupedNotMap(t *testing.T) {
+	var result []string
+	assert.Equal(t, ErrUnsupportedValueType, scanGrouped(&result, nil))
+}
@@ -0,0 +1,103 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrUnqualifiedWrite is an error that indicates an UPDATE or DELETE without a
+// WHERE clause was rejected by WithGuardUnqualifiedWrites.
+var ErrUnqualifiedWrite = errors.New("unqualified UPDATE/DELETE rejected, add a WHERE clause or use AllowUnqualifiedWriteCtx")
+
+type allowUnqualifiedKey struct{}
+
+// WithGuardUnqualifiedWrites rejects UPDATE and DELETE statements that have no
+// WHERE clause with ErrUnqualifiedWrite before they hit the database, so a
+// forgotten WHERE can't wipe a table. Intentional full-table writes opt in per
+// call via AllowUnqualifiedWriteCtx.
+func WithGuardUnqualifiedWrites() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.guardUnqualified = true
+	}
+}
+
+// AllowUnqualifiedWriteCtx marks calls made with the returned context as
+// intentionally unqualified, bypassing WithGuardUnqualifiedWrites.
+func AllowUnqualifiedWriteCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowUnqualifiedKey{}, true)
+}
+
+func unqualifiedWriteAllowed(ctx context.Context) bool {
+	allowed, ok := ctx.Value(allowUnqualifiedKey{}).(bool)
+	return ok && allowed
+}
+
+func (db *commonSqlConn) checkUnqualifiedWrite(ctx context.Context, q string) error {
+	if !db.guardUnqualified {
+		return nil
+	}
+
+	switch db.classify(q) {
+	case "UPDATE", "DELETE":
+	default:
+		return nil
+	}
+
+	if hasWhereClause(q) || unqualifiedWriteAllowed(ctx) {
+		return nil
+	}
+
+	return ErrUnqualifiedWrite
+}
+
+// hasWhereClause reports whether q contains a WHERE keyword outside quoted
+// strings and comments. A WHERE in a subquery satisfies it too, the guard only
+// catches the statement having no qualification at all.
+func hasWhereClause(q string) bool {
+	n := len(q)
+	for i := 0; i < n; {
+		c := q[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i++
+			for i < n {
+				if q[i] == '\\' && c != '`' && i+1 < n {
+					i += 2
+					continue
+				}
+				if q[i] == c {
+					i++
+					break
+				}
+				i++
+			}
+		case c == '-' && i+1 < n && q[i+1] == '-':
+			for i < n && q[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < n && q[i+1] == '*':
+			idx := strings.Index(q[i+2:], "*/")
+			if idx < 0 {
+				return false
+			}
+			i += idx + 4
+		case isSqlLetter(c):
+			start := i
+			for i < n && isSqlLetter(q[i]) {
+				i++
+			}
+			if strings.EqualFold(q[start:i], "where") {
+				return true
+			}
+		default:
+			i++
+		}
+	}
+
+	return false
+}
+
+func isSqlLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
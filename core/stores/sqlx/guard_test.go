@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardUnqualifiedWrites(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users set name").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from sessions").WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		conn := NewSqlConnFromDB(db, WithGuardUnqualifiedWrites())
+		_, err := conn.Exec("update users set name = 'a'")
+		assert.Equal(t, ErrUnqualifiedWrite, err)
+		_, err = conn.Exec("delete from users")
+		assert.Equal(t, ErrUnqualifiedWrite, err)
+
+		// qualified writes pass
+		_, err = conn.Exec("update users set name = 'a' where id = 1")
+		assert.Nil(t, err)
+		// intentional full-table writes opt in per call
+		_, err = conn.ExecCtx(AllowUnqualifiedWriteCtx(context.Background()),
+			"delete from sessions")
+		assert.Nil(t, err)
+		// other statement types are untouched
+		_, err = conn.Exec("insert into users (name) values ('a')")
+		assert.Nil(t, err)
+	})
+}
+
+func TestHasWhereClause(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"update users set name = 'a' where id = 1", true},
+		{"UPDATE users SET name = 'a' WHERE id = 1", true},
+		{"update users set name = 'where'", false},
+		{"update users set name = 'a' -- where id = 1", false},
+		{"update users set name = 'a' /* where id = 1 */", false},
+		{"delete from users", false},
+		{"delete from users where id in (select id from banned)", true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, hasWhereClause(test.query), test.query)
+	}
+}
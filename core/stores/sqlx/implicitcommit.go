@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// implicitCommitStmts are the statement types that make MySQL commit the
+// current transaction implicitly.
+var implicitCommitStmts = map[string]bool{
+	"CREATE":   true,
+	"ALTER":    true,
+	"DROP":     true,
+	"TRUNCATE": true,
+	"RENAME":   true,
+	"GRANT":    true,
+	"REVOKE":   true,
+}
+
+// implicitCommitGuard wraps a transaction session on MySQL, logging when a
+// statement that implicitly commits runs inside the transaction.
+type implicitCommitGuard struct {
+	trans
+}
+
+// BeforeCommit forwards to the wrapped session, so commit hooks stay available
+// under WithWarnImplicitCommit.
+func (g implicitCommitGuard) BeforeCommit(fn func() error) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.BeforeCommit(fn)
+	}
+}
+
+// AfterCommit forwards to the wrapped session, see BeforeCommit.
+func (g implicitCommitGuard) AfterCommit(fn func()) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.AfterCommit(fn)
+	}
+}
+
+func (g implicitCommitGuard) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return g.ExecCtx(context.Background(), q, args...)
+}
+
+func (g implicitCommitGuard) ExecCtx(ctx context.Context, q string, args ...interface{}) (
+	sql.Result, error) {
+	if kind := classifyStatement(q); implicitCommitStmts[kind] {
+		logx.WithContext(ctx).Errorf(
+			"%s statement inside a transaction commits it implicitly on mysql, "+
+				"it won't be rolled back - %s", kind, q)
+	}
+
+	return g.trans.ExecCtx(ctx, q, args...)
+}
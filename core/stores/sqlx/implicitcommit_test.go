@@ -0,0 +1,48 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImplicitCommitGuardPassesStatementsThrough(t *testing.T) {
+	mock := &mockTx{}
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithWarnImplicitCommit()), func(ctx context.Context, session Session) error {
+			if _, err := session.ExecCtx(ctx, "ALTER TABLE users ADD COLUMN age int"); err != nil {
+				return err
+			}
+			_, err := session.ExecCtx(ctx, "UPDATE users SET age = ?", 1)
+			return err
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, mockCommit, mock.status)
+	assert.Equal(t, []string{
+		"ALTER TABLE users ADD COLUMN age int",
+		"UPDATE users SET age = ?",
+	}, mock.queries)
+}
+
+func TestImplicitCommitStmts(t *testing.T) {
+	tests := []struct {
+		query string
+		warn  bool
+	}{
+		{query: "CREATE INDEX idx_age ON users (age)", warn: true},
+		{query: "ALTER TABLE users ADD COLUMN age int", warn: true},
+		{query: "DROP TABLE users", warn: true},
+		{query: "TRUNCATE TABLE users", warn: true},
+		{query: "RENAME TABLE users TO members", warn: true},
+		{query: "INSERT INTO users (age) VALUES (?)", warn: false},
+		{query: "UPDATE users SET age = ?", warn: false},
+		{query: "DELETE FROM users WHERE id = ?", warn: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.warn, implicitCommitStmts[classifyStatement(test.query)])
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// InsertOption is an alias of UpdateOption, InsertCtx and UpdateCtx share
+// their options.
+type InsertOption = UpdateOption
+
+// InsertCtx builds an INSERT statement from the db tagged fields of data and
+// executes it on session. Unlike UpdateCtx, zero fields are included, an
+// insert states the full row; nil pointer fields insert NULL. With
+// WithTimestamps, zero created/updated columns are filled with time.Now().
+func InsertCtx(ctx context.Context, session Session, table string, data interface{},
+	opts ...InsertOption) (sql.Result, error) {
+	var o updateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	columns, args, err := buildInsertColumns(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fillTimestamp(columns, args, o.createdCol)
+	fillTimestamp(columns, args, o.updatedCol)
+
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(table)
+	builder.WriteString(" (")
+	builder.WriteString(strings.Join(columns, ", "))
+	builder.WriteString(") VALUES (")
+	builder.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+	builder.WriteString(")")
+
+	return session.ExecCtx(ctx, builder.String(), args...)
+}
+
+func buildInsertColumns(data interface{}) ([]string, []interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Struct {
+		return nil, nil, ErrUnsupportedValueType
+	}
+
+	var columns []string
+	var args []interface{}
+	rt := mapping.Deref(v.Type())
+	for i := 0; i < rt.NumField(); i++ {
+		key := parseTagName(rt.Field(i))
+		if len(key) == 0 {
+			continue
+		}
+
+		field := v.Field(i)
+		columns = append(columns, key)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				args = append(args, nil)
+			} else {
+				args = append(args, field.Elem().Interface())
+			}
+		} else {
+			args = append(args, field.Interface())
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, nil, ErrNoColumnsToUpdate
+	}
+
+	return columns, args, nil
+}
+
+// fillTimestamp sets the arg of column to time.Now() when the column is part
+// of the insert and its value was left zero.
+func fillTimestamp(columns []string, args []interface{}, column string) {
+	if len(column) == 0 {
+		return
+	}
+
+	for i, each := range columns {
+		if each != column {
+			continue
+		}
+		if args[i] == nil || reflect.ValueOf(args[i]).IsZero() {
+			args[i] = time.Now()
+		}
+		return
+	}
+}
+
+// hasTaggedColumn reports whether the db tagged fields of data declare column.
+func hasTaggedColumn(data interface{}, column string) bool {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+
+	rt := mapping.Deref(v.Type())
+	for i := 0; i < rt.NumField(); i++ {
+		if parseTagName(rt.Field(i)) == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, each := range columns {
+		if each == column {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`INSERT INTO users \(name, age\) VALUES \(\?, \?\)`).
+			WithArgs("first", 0).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := InsertCtx(context.Background(), NewSqlConnFromDB(db), "users", struct {
+			Name string `db:"name"`
+			Age  int    `db:"age"`
+		}{Name: "first"})
+		assert.Nil(t, err)
+	})
+}
+
+func TestInsertCtxNilPointer(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`INSERT INTO users \(name, nickname\) VALUES \(\?, \?\)`).
+			WithArgs("first", nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := InsertCtx(context.Background(), NewSqlConnFromDB(db), "users", struct {
+			Name     string  `db:"name"`
+			Nickname *string `db:"nickname"`
+		}{Name: "first"})
+		assert.Nil(t, err)
+	})
+}
+
+func TestInsertCtxTimestamps(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`INSERT INTO users \(name, created_at, updated_at\) VALUES \(\?, \?, \?\)`).
+			WithArgs("first", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := InsertCtx(context.Background(), NewSqlConnFromDB(db), "users", struct {
+			Name      string    `db:"name"`
+			CreatedAt time.Time `db:"created_at"`
+			UpdatedAt time.Time `db:"updated_at"`
+		}{Name: "first"}, WithTimestamps("created_at", "updated_at"))
+		assert.Nil(t, err)
+	})
+}
+
+func TestInsertCtxTimestampsNoColumns(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`INSERT INTO users \(name\) VALUES \(\?\)`).
+			WithArgs("first").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		_, err := InsertCtx(context.Background(), NewSqlConnFromDB(db), "users", struct {
+			Name string `db:"name"`
+		}{Name: "first"}, WithTimestamps("created_at", "updated_at"))
+		assert.Nil(t, err)
+	})
+}
+
+func TestUpdateCtxTimestamps(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`UPDATE users SET name = \?, updated_at = \? WHERE id = \?`).
+			WithArgs("first", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := UpdateWithOptionsCtx(context.Background(), NewSqlConnFromDB(db), "users",
+			struct {
+				Name      string    `db:"name"`
+				UpdatedAt time.Time `db:"updated_at"`
+			}{Name: "first"},
+			"id = ?", []interface{}{1}, WithTimestamps("created_at", "updated_at"))
+		assert.Nil(t, err)
+	})
+}
+
+func TestUpdateCtxTimestampsExplicitValueWins(t *testing.T) {
+	explicit := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`UPDATE users SET name = \?, updated_at = \? WHERE id = \?`).
+			WithArgs("first", explicit, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := UpdateWithOptionsCtx(context.Background(), NewSqlConnFromDB(db), "users",
+			struct {
+				Name      string    `db:"name"`
+				UpdatedAt time.Time `db:"updated_at"`
+			}{Name: "first", UpdatedAt: explicit},
+			"id = ?", []interface{}{1}, WithTimestamps("created_at", "updated_at"))
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrCursorColumnNotFound is an error that indicates the cursor column has no
+// matching db tagged field in the destination struct.
+var ErrCursorColumnNotFound = errors.New("cursor column not found in destination struct")
+
+// QueryRowsKeysetCtx runs keyset pagination over baseQuery, scanning one page into v,
+// a pointer to a slice of structs. On the first page pass a nil afterValue, following
+// pages pass the cursor returned by the previous call. The cursor is taken from the
+// cursorColumn field of the last row, or nil if the page is empty.
+func QueryRowsKeysetCtx(ctx context.Context, session Session, v interface{}, baseQuery,
+	cursorColumn string, afterValue interface{}, limit int, args ...interface{}) (interface{}, error) {
+	query := baseQuery
+	if afterValue != nil {
+		query = fmt.Sprintf("%s WHERE %s > ?", query, cursorColumn)
+		args = append(args, afterValue)
+	}
+	query = fmt.Sprintf("%s ORDER BY %s LIMIT ?", query, cursorColumn)
+	args = append(args, limit)
+
+	if err := session.QueryRowsCtx(ctx, v, query, args...); err != nil {
+		return nil, err
+	}
+
+	return keysetCursor(v, cursorColumn)
+}
+
+func keysetCursor(v interface{}, cursorColumn string) (interface{}, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice {
+		return nil, ErrUnsupportedValueType
+	}
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+
+	last := reflect.Indirect(rv.Index(rv.Len() - 1))
+	if last.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedValueType
+	}
+
+	rt := mapping.Deref(last.Type())
+	for i := 0; i < rt.NumField(); i++ {
+		if parseTagName(rt.Field(i)) == cursorColumn {
+			return reflect.Indirect(last.Field(i)).Interface(), nil
+		}
+	}
+
+	return nil, ErrCursorColumnNotFound
+}
@@ -0,0 +1,60 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsKeysetCtx(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`select id, name from users ORDER BY id LIMIT \?`).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "a").AddRow(2, "b"))
+		mock.ExpectQuery(`select id, name from users WHERE id > \? ORDER BY id LIMIT \?`).
+			WithArgs(int64(2), 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "c"))
+
+		conn := NewSqlConnFromDB(db)
+		var users []user
+		cursor, err := QueryRowsKeysetCtx(context.Background(), conn, &users,
+			"select id, name from users", "id", nil, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(users))
+		assert.Equal(t, int64(2), cursor)
+
+		users = nil
+		cursor, err = QueryRowsKeysetCtx(context.Background(), conn, &users,
+			"select id, name from users", "id", cursor, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, int64(3), cursor)
+	})
+}
+
+func TestKeysetCursor(t *testing.T) {
+	type user struct {
+		ID int64 `db:"id"`
+	}
+
+	var empty []user
+	cursor, err := keysetCursor(&empty, "id")
+	assert.Nil(t, err)
+	assert.Nil(t, cursor)
+
+	_, err = keysetCursor(&user{}, "id")
+	assert.Equal(t, ErrUnsupportedValueType, err)
+
+	users := []user{{ID: 9}}
+	_, err = keysetCursor(&users, "unknown")
+	assert.Equal(t, ErrCursorColumnNotFound, err)
+}
@@ -0,0 +1,135 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/syncx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const (
+	// defaultLagCheckInterval is how often the replica lag is sampled.
+	defaultLagCheckInterval = time.Second * 10
+	slaveStatusQuery        = "SHOW SLAVE STATUS"
+	lagColumnName           = "Seconds_Behind_Master"
+)
+
+var errNoLagColumn = errors.New("no " + lagColumnName + " column in slave status")
+
+// lagMonitor periodically samples the slave's replication lag, so routing can
+// skip a replica that's too far behind.
+type lagMonitor struct {
+	maxLag   time.Duration
+	interval time.Duration
+	once     sync.Once
+	lagging  *syncx.AtomicBool
+}
+
+// WithMaxReplicaLag makes read routing skip the slave when its replication lag
+// exceeds maxLag, falling back to master until the slave catches up. The lag is
+// sampled in the background via SHOW SLAVE STATUS, so it only works on MySQL.
+func WithMaxReplicaLag(maxLag time.Duration) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.lagMonitor = &lagMonitor{
+			maxLag:   maxLag,
+			interval: defaultLagCheckInterval,
+			lagging:  syncx.NewAtomicBool(),
+		}
+	}
+}
+
+func (m *lagMonitor) start(db *commonSqlConn) {
+	m.once.Do(func() {
+		threading.GoSafe(func() {
+			ticker := time.NewTicker(m.interval)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				m.check(db)
+			}
+		})
+	})
+}
+
+func (m *lagMonitor) check(db *commonSqlConn) {
+	slave, ok := db.datasource["slave"]
+	if !ok {
+		return
+	}
+
+	conn, err := db.connProv(slave)
+	if err != nil {
+		return
+	}
+
+	lag, err := replicaLag(conn)
+	if err != nil {
+		logx.Errorf("error on checking replica lag: %v", err)
+		return
+	}
+
+	if lag > m.maxLag && !m.lagging.True() {
+		logx.Errorf("replica lagging %v behind master, routing reads to master", lag)
+	}
+	m.lagging.Set(lag > m.maxLag)
+}
+
+// slaveHealthy reports whether reads may be routed to the slave.
+func (db *commonSqlConn) slaveHealthy() bool {
+	if db.lagMonitor == nil {
+		return true
+	}
+
+	db.lagMonitor.start(db)
+	return !db.lagMonitor.lagging.True()
+}
+
+func replicaLag(conn *sql.DB) (time.Duration, error) {
+	rows, err := conn.Query(slaveStatusQuery)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("not a replica")
+	}
+
+	values := make([]interface{}, len(columns))
+	holders := make([]sql.NullString, len(columns))
+	for i := range values {
+		values[i] = &holders[i]
+	}
+	if err := rows.Scan(values...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != lagColumnName {
+			continue
+		}
+		if !holders[i].Valid {
+			return 0, errors.New("replication not running")
+		}
+
+		seconds, err := strconv.Atoi(holders[i].String)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return 0, errNoLagColumn
+}
@@ -0,0 +1,54 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaLag(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SHOW SLAVE STATUS").WillReturnRows(
+			sqlmock.NewRows([]string{"Slave_IO_Running", "Seconds_Behind_Master"}).
+				AddRow("Yes", "120"))
+
+		lag, err := replicaLag(db)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Minute*2, lag)
+	})
+}
+
+func TestReplicaLagNotRunning(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SHOW SLAVE STATUS").WillReturnRows(
+			sqlmock.NewRows([]string{"Slave_IO_Running", "Seconds_Behind_Master"}).
+				AddRow("No", nil))
+
+		_, err := replicaLag(db)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestLaggingSlaveSkipped(t *testing.T) {
+	conn := NewSqlConnCluster("mysql", map[string]string{
+		"master": "master-dsn",
+		"slave":  "slave-dsn",
+	}, true, WithMaxReplicaLag(time.Second)).(*commonSqlConn)
+
+	datasource, err := conn.DataSourceResp("SELECT name FROM users")
+	assert.Nil(t, err)
+	assert.Equal(t, "slave-dsn", datasource)
+
+	conn.lagMonitor.lagging.Set(true)
+	datasource, err = conn.DataSourceResp("SELECT name FROM users")
+	assert.Nil(t, err)
+	assert.Equal(t, "master-dsn", datasource)
+
+	conn.lagMonitor.lagging.Set(false)
+	datasource, err = conn.DataSourceResp("SELECT name FROM users")
+	assert.Nil(t, err)
+	assert.Equal(t, "slave-dsn", datasource)
+}
@@ -0,0 +1,179 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrLazyUpgradeUnsupported is an error that indicates a lazy transaction is
+// combined with transact upgrade, whose slave-then-master replay needs the
+// transaction open from the start.
+var ErrLazyUpgradeUnsupported = errors.New("lazy transactions cannot be combined with transact upgrade")
+
+// WithLazyTx defers BEGIN until the first statement of fn actually runs, so a
+// transactional method that conditionally skips all its statements never holds
+// a connection or a snapshot. When nothing ran there is also nothing to commit
+// or roll back, both become no-ops.
+func WithLazyTx() TransactOption {
+	return func(o *txOptions) {
+		o.lazy = true
+	}
+}
+
+// lazyTx is a transaction session that begins the underlying transaction on
+// the first statement, running the configured init statements right after.
+type lazyTx struct {
+	ctx   context.Context
+	conn  *sql.DB
+	begin beginnable
+	opts  *txOptions
+	lock  sync.Mutex
+	tx    trans
+}
+
+func newLazyTx(ctx context.Context, conn *sql.DB, b beginnable, opts *txOptions) *lazyTx {
+	return &lazyTx{
+		ctx:   ctx,
+		conn:  conn,
+		begin: b,
+		opts:  opts,
+	}
+}
+
+// session returns the underlying transaction, beginning it on the first call.
+func (t *lazyTx) session() (trans, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.tx != nil {
+		return t.tx, nil
+	}
+
+	tx, err := t.begin(t.ctx, t.conn, t.opts.sqlOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range t.opts.initStatements() {
+		if _, err := tx.ExecCtx(t.ctx, stmt); err != nil {
+			if e := tx.Rollback(); e != nil {
+				return nil, fmt.Errorf("init statement failed: %s, rollback failed: %w", err, e)
+			}
+			return nil, err
+		}
+	}
+
+	t.tx = tx
+	return tx, nil
+}
+
+func (t *lazyTx) Commit() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.tx == nil {
+		return nil
+	}
+
+	return t.tx.Commit()
+}
+
+func (t *lazyTx) Rollback() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.tx == nil {
+		return nil
+	}
+
+	return t.tx.Rollback()
+}
+
+func (t *lazyTx) InTransaction() bool {
+	return true
+}
+
+func (t *lazyTx) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return t.ExecCtx(context.Background(), q, args...)
+}
+
+func (t *lazyTx) ExecCtx(ctx context.Context, q string, args ...interface{}) (sql.Result, error) {
+	tx, err := t.session()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.ExecCtx(ctx, q, args...)
+}
+
+func (t *lazyTx) Prepare(q string) (StmtSession, error) {
+	return t.PrepareCtx(context.Background(), q)
+}
+
+func (t *lazyTx) PrepareCtx(ctx context.Context, q string) (StmtSession, error) {
+	tx, err := t.session()
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.PrepareCtx(ctx, q)
+}
+
+func (t *lazyTx) QueryRow(v interface{}, q string, args ...interface{}) error {
+	return t.QueryRowCtx(context.Background(), v, q, args...)
+}
+
+func (t *lazyTx) QueryRowCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	tx, err := t.session()
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowCtx(ctx, v, q, args...)
+}
+
+func (t *lazyTx) QueryRowPartial(v interface{}, q string, args ...interface{}) error {
+	return t.QueryRowPartialCtx(context.Background(), v, q, args...)
+}
+
+func (t *lazyTx) QueryRowPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	tx, err := t.session()
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowPartialCtx(ctx, v, q, args...)
+}
+
+func (t *lazyTx) QueryRows(v interface{}, q string, args ...interface{}) error {
+	return t.QueryRowsCtx(context.Background(), v, q, args...)
+}
+
+func (t *lazyTx) QueryRowsCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	tx, err := t.session()
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowsCtx(ctx, v, q, args...)
+}
+
+func (t *lazyTx) QueryRowsPartial(v interface{}, q string, args ...interface{}) error {
+	return t.QueryRowsPartialCtx(context.Background(), v, q, args...)
+}
+
+func (t *lazyTx) QueryRowsPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	tx, err := t.session()
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRowsPartialCtx(ctx, v, q, args...)
+}
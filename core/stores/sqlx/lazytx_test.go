@@ -0,0 +1,81 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyTxNoStatements(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		// no Begin/Commit expected, fn runs no statement so no transaction opens
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			assert.True(t, session.InTransaction())
+			return nil
+		}, WithLazyTx())
+		assert.Nil(t, err)
+	})
+}
+
+func TestLazyTxBeginsOnFirstStatement(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`update users set name = \?`).WithArgs("any").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("any"))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			if _, err := session.Exec("update users set name = ?", "any"); err != nil {
+				return err
+			}
+
+			var name string
+			return session.QueryRow(&name, "select name from users where id = ?", 1)
+		}, WithLazyTx())
+		assert.Nil(t, err)
+	})
+}
+
+func TestLazyTxRollback(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`update users set name = \?`).WithArgs("any").
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			_, err := session.Exec("update users set name = ?", "any")
+			return err
+		}, WithLazyTx())
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestLazyTxBeginTx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := NewSqlConnFromDB(db)
+		session, commit, _, err := BeginTx(context.Background(), conn, WithLazyTx())
+		assert.Nil(t, err)
+		assert.True(t, session.InTransaction())
+		// nothing ran, the commit is a no-op
+		assert.Nil(t, commit())
+	})
+}
+
+func TestLazyTxUpgradeRejected(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			return nil
+		}, WithLazyTx(), WithTransactUpgrade())
+		assert.ErrorIs(t, err, ErrLazyUpgradeUnsupported)
+	})
+}
@@ -0,0 +1,81 @@
+package sqlx
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// LifecycleHooks get called around connection pool lifecycle, for structured
+// logging and metrics. The dsn passed to the hooks is desensitized.
+type LifecycleHooks struct {
+	// OnOpen is called the first time a pool is provided for a datasource.
+	OnOpen func(role, dsn string)
+	// OnClose is called when the pool of a datasource is closed.
+	OnClose func(role, dsn string)
+	// OnError is called when getting the pool of a datasource fails.
+	OnError func(role, dsn string)
+}
+
+var closeListeners = struct {
+	sync.Mutex
+	listeners map[string][]func()
+}{
+	listeners: make(map[string][]func()),
+}
+
+// WithLifecycleHooks customizes a SqlConn with hooks around pool lifecycle.
+func WithLifecycleHooks(hooks LifecycleHooks) SqlOption {
+	return func(conn *commonSqlConn) {
+		base := conn.connProv
+		var lock sync.Mutex
+		opened := make(map[string]bool)
+		conn.connProv = func(ds string) (*sql.DB, error) {
+			db, err := base(ds)
+			if err != nil {
+				return nil, err
+			}
+
+			lock.Lock()
+			first := !opened[ds]
+			opened[ds] = true
+			lock.Unlock()
+			if first {
+				if hooks.OnOpen != nil {
+					hooks.OnOpen(conn.roleOf(ds), desensitize(ds))
+				}
+				if hooks.OnClose != nil {
+					registerCloseListener(ds, func() {
+						hooks.OnClose(conn.roleOf(ds), desensitize(ds))
+					})
+				}
+			}
+
+			return db, nil
+		}
+
+		if hooks.OnError != nil {
+			baseOnError := conn.onError
+			conn.onError = func(ds string, err error) {
+				baseOnError(ds, err)
+				hooks.OnError(conn.roleOf(ds), desensitize(ds))
+			}
+		}
+	}
+}
+
+func registerCloseListener(server string, listener func()) {
+	closeListeners.Lock()
+	closeListeners.listeners[server] = append(closeListeners.listeners[server], listener)
+	closeListeners.Unlock()
+}
+
+func notifyCloseListeners(server string) {
+	closeListeners.Lock()
+	listeners := closeListeners.listeners[server]
+	delete(closeListeners.listeners, server)
+	closeListeners.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
@@ -0,0 +1,44 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLifecycleHooksOnOpen(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("first"))
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("second"))
+
+		var opens int
+		conn := NewSqlConnFromDB(db, WithLifecycleHooks(LifecycleHooks{
+			OnOpen: func(role, dsn string) {
+				opens++
+			},
+		}))
+
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users limit 1"))
+		assert.Nil(t, conn.QueryRow(&name, "select name from users limit 1"))
+		// only the first provision of the pool fires OnOpen
+		assert.Equal(t, 1, opens)
+	})
+}
+
+func TestCloseListeners(t *testing.T) {
+	var closed bool
+	registerCloseListener("listener-test-dsn", func() {
+		closed = true
+	})
+	notifyCloseListeners("listener-test-dsn")
+	assert.True(t, closed)
+	// listeners fire only once
+	closed = false
+	notifyCloseListeners("listener-test-dsn")
+	assert.False(t, closed)
+}
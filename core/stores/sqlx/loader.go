@@ -0,0 +1,213 @@
+package sqlx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/threading"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultLoaderWindow is how long a loader waits for more keys before flushing.
+	defaultLoaderWindow = time.Millisecond * 10
+	// defaultLoaderBatchSize flushes a batch early once this many keys are collected.
+	defaultLoaderBatchSize = 100
+)
+
+type (
+	// LoaderKeyFn extracts the key of a scanned row, used to match rows
+	// back to the keys that were loaded.
+	LoaderKeyFn func(row interface{}) interface{}
+
+	// LoaderOption defines the method to customize a Loader.
+	LoaderOption func(loader *Loader)
+
+	// Loader coalesces single-key reads issued within a short window into one
+	// IN (...) query, to avoid N+1 queries. Results are cached for the lifetime
+	// of the Loader, so create one Loader per request.
+	Loader struct {
+		conn      SqlConn
+		query     string
+		prototype reflect.Type
+		keyFn     LoaderKeyFn
+		window    time.Duration
+		batchSize int
+		lock      sync.Mutex
+		cache     map[interface{}]loaderResult
+		pending   *loaderCall
+	}
+
+	loaderCall struct {
+		keys    []interface{}
+		links   []oteltrace.Link
+		waiters map[interface{}][]chan loaderResult
+		timer   *time.Timer
+		// flushed guards against the timer and the batch-full path both
+		// flushing the same batch, it's protected by the Loader lock.
+		flushed bool
+	}
+
+	loaderResult struct {
+		value interface{}
+		err   error
+	}
+)
+
+// NewLoader returns a Loader that runs query for batches of keys, where query
+// contains a single `IN (?)` whose placeholder is expanded to the batch size.
+// prototype is a zero value of the row struct, and keyFn maps a scanned row
+// back to its key.
+func NewLoader(conn SqlConn, query string, prototype interface{}, keyFn LoaderKeyFn,
+	opts ...LoaderOption) *Loader {
+	loader := &Loader{
+		conn:      conn,
+		query:     query,
+		prototype: reflect.TypeOf(prototype),
+		keyFn:     keyFn,
+		window:    defaultLoaderWindow,
+		batchSize: defaultLoaderBatchSize,
+		cache:     make(map[interface{}]loaderResult),
+	}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// WithLoaderBatchSize customizes a Loader to flush early once size keys are collected.
+func WithLoaderBatchSize(size int) LoaderOption {
+	return func(loader *Loader) {
+		loader.batchSize = size
+	}
+}
+
+// WithLoaderWindow customizes a Loader with given batching window.
+func WithLoaderWindow(window time.Duration) LoaderOption {
+	return func(loader *Loader) {
+		loader.window = window
+	}
+}
+
+// Load returns the row for given key, joining the current batch if one is open.
+// Keys missing from the result set return ErrNotFound.
+func (l *Loader) Load(ctx context.Context, key interface{}) (interface{}, error) {
+	l.lock.Lock()
+	if result, ok := l.cache[key]; ok {
+		l.lock.Unlock()
+		return result.value, result.err
+	}
+
+	call := l.pending
+	if call == nil {
+		call = &loaderCall{
+			waiters: make(map[interface{}][]chan loaderResult),
+		}
+		call.timer = time.AfterFunc(l.window, func() {
+			l.flush(call)
+		})
+		l.pending = call
+	}
+
+	ch := make(chan loaderResult, 1)
+	if _, ok := call.waiters[key]; !ok {
+		call.keys = append(call.keys, key)
+	}
+	call.waiters[key] = append(call.waiters[key], ch)
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		call.addLink(sc)
+	}
+
+	full := len(call.keys) >= l.batchSize
+	if full {
+		l.pending = nil
+		call.timer.Stop()
+	}
+	l.lock.Unlock()
+
+	if full {
+		threading.GoSafe(func() {
+			l.flush(call)
+		})
+	}
+
+	select {
+	case result := <-ch:
+		return result.value, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Loader) flush(call *loaderCall) {
+	l.lock.Lock()
+	if call.flushed {
+		// Stop() can't cancel an already-fired timer, so the timer and the
+		// batch-full path may race here, only the first one proceeds
+		l.lock.Unlock()
+		return
+	}
+	call.flushed = true
+	if l.pending == call {
+		l.pending = nil
+	}
+	l.lock.Unlock()
+
+	rows := reflect.New(reflect.SliceOf(l.prototype))
+	q := expandInQuery(l.query, len(call.keys))
+	// the batched query outlives any single caller, so it runs on a detached
+	// context linked back to the span of every request it serves.
+	ctx := withSpanLinks(context.Background(), call.links)
+	err := l.conn.QueryRowsPartialCtx(ctx, rows.Interface(), q, call.keys...)
+
+	results := make(map[interface{}]loaderResult, len(call.keys))
+	if err != nil {
+		for _, key := range call.keys {
+			results[key] = loaderResult{err: err}
+		}
+	} else {
+		slice := rows.Elem()
+		for i := 0; i < slice.Len(); i++ {
+			row := slice.Index(i).Interface()
+			results[l.keyFn(row)] = loaderResult{value: row}
+		}
+		for _, key := range call.keys {
+			if _, ok := results[key]; !ok {
+				results[key] = loaderResult{err: ErrNotFound}
+			}
+		}
+	}
+
+	l.lock.Lock()
+	for key, result := range results {
+		l.cache[key] = result
+	}
+	l.lock.Unlock()
+
+	for key, waiters := range call.waiters {
+		for _, waiter := range waiters {
+			waiter <- results[key]
+		}
+	}
+}
+
+// addLink records the caller's span once, so the flushed query span links back
+// to each distinct request span in the batch.
+func (call *loaderCall) addLink(sc oteltrace.SpanContext) {
+	for _, link := range call.links {
+		if link.SpanContext.Equal(sc) {
+			return
+		}
+	}
+
+	call.links = append(call.links, oteltrace.Link{SpanContext: sc})
+}
+
+func expandInQuery(query string, size int) string {
+	placeholders := strings.Repeat("?,", size)
+	return strings.Replace(query, "(?)", "("+placeholders[:len(placeholders)-1]+")", 1)
+}
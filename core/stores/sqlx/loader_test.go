@@ -0,0 +1,148 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLoaderBatchesKeys(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users where id in").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "first").
+				AddRow(2, "second"))
+
+		type user struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		loader := NewLoader(NewSqlConnFromDB(db), "select id, name from users where id in (?)",
+			user{}, func(row interface{}) interface{} {
+				return row.(user).ID
+			}, WithLoaderWindow(time.Millisecond*20))
+
+		var wg sync.WaitGroup
+		names := make([]string, 2)
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				value, err := loader.Load(context.Background(), int64(i+1))
+				assert.Nil(t, err)
+				names[i] = value.(user).Name
+			}(i)
+		}
+		wg.Wait()
+		assert.ElementsMatch(t, []string{"first", "second"}, names)
+	})
+}
+
+func TestLoaderMissingKey(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users where id in").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}))
+
+		type user struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		loader := NewLoader(NewSqlConnFromDB(db), "select id, name from users where id in (?)",
+			user{}, func(row interface{}) interface{} {
+				return row.(user).ID
+			}, WithLoaderWindow(time.Millisecond))
+
+		_, err := loader.Load(context.Background(), int64(404))
+		assert.Equal(t, ErrNotFound, err)
+		// missing keys are cached as well
+		_, err = loader.Load(context.Background(), int64(404))
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestLoaderSpanLinks(t *testing.T) {
+	otp := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(otp)
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users where id in").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "first"))
+
+		type user struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		loader := NewLoader(NewSqlConnFromDB(db), "select id, name from users where id in (?)",
+			user{}, func(row interface{}) interface{} {
+				return row.(user).ID
+			}, WithLoaderWindow(time.Millisecond))
+
+		ctx, span := otel.Tracer("foo").Start(context.Background(), "bar")
+		_, err := loader.Load(ctx, int64(1))
+		span.End()
+		assert.Nil(t, err)
+
+		for _, ended := range recorder.Ended() {
+			if ended.Name() != spanName {
+				continue
+			}
+
+			if assert.Equal(t, 1, len(ended.Links())) {
+				assert.Equal(t, span.SpanContext().SpanID(),
+					ended.Links()[0].SpanContext.SpanID())
+			}
+			return
+		}
+		t.Fatal("no sql span recorded for the flushed query")
+	})
+}
+
+func TestExpandInQuery(t *testing.T) {
+	assert.Equal(t, "select * from users where id in (?)",
+		expandInQuery("select * from users where id in (?)", 1))
+	assert.Equal(t, "select * from users where id in (?,?,?)",
+		expandInQuery("select * from users where id in (?)", 3))
+}
+
+func TestLoaderFlushRunsOnce(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users where id in").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "first"))
+
+		type user struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+		loader := NewLoader(NewSqlConnFromDB(db), "select id, name from users where id in (?)",
+			user{}, func(row interface{}) interface{} {
+				return row.(user).ID
+			})
+
+		ch := make(chan loaderResult, 1)
+		call := &loaderCall{
+			keys:    []interface{}{int64(1)},
+			waiters: map[interface{}][]chan loaderResult{int64(1): {ch}},
+		}
+		call.timer = time.AfterFunc(time.Hour, func() {})
+		defer call.timer.Stop()
+
+		// the timer and the batch-full path may both flush the same batch,
+		// only the first run queries and notifies the waiters
+		loader.flush(call)
+		loader.flush(call)
+		assert.Equal(t, 1, len(ch))
+		result := <-ch
+		assert.Nil(t, result.err)
+		assert.Equal(t, "first", result.value.(user).Name)
+	})
+}
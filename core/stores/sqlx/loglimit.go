@@ -0,0 +1,53 @@
+package sqlx
+
+import "context"
+
+// defaultMaxLogQueryLen caps query strings in logs and trace attributes, so a
+// generated IN list with thousands of values doesn't blow up log storage.
+const defaultMaxLogQueryLen = 4096
+
+type maxLogQueryLenKey struct{}
+
+// WithMaxLogQueryLen truncates query strings to n characters, with a trailing
+// ellipsis, wherever they appear in info, slow and error logs and in trace
+// attributes. It bounds size only, unlike WithQueryNormalizer which rewrites
+// literals, and the default cap is defaultMaxLogQueryLen when not set.
+// The executed SQL is never changed.
+func WithMaxLogQueryLen(n int) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.maxLogQueryLen = n
+	}
+}
+
+func (db *commonSqlConn) markLogQueryLen(ctx context.Context) context.Context {
+	if db.maxLogQueryLen <= 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, maxLogQueryLenKey{}, db.maxLogQueryLen)
+}
+
+func (db *commonSqlConn) logQueryLimit() int {
+	if db.maxLogQueryLen > 0 {
+		return db.maxLogQueryLen
+	}
+
+	return defaultMaxLogQueryLen
+}
+
+func truncateLogQuery(ctx context.Context, q string) string {
+	limit := defaultMaxLogQueryLen
+	if n, ok := ctx.Value(maxLogQueryLenKey{}).(int); ok {
+		limit = n
+	}
+
+	return limitQueryLen(q, limit)
+}
+
+func limitQueryLen(q string, limit int) string {
+	if len(q) <= limit {
+		return q
+	}
+
+	return q[:limit] + "..."
+}
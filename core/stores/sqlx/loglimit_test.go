@@ -0,0 +1,47 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitQueryLen(t *testing.T) {
+	assert.Equal(t, "select 1", limitQueryLen("select 1", 100))
+	assert.Equal(t, "select 1", limitQueryLen("select 1", 8))
+	assert.Equal(t, "sel...", limitQueryLen("select 1", 3))
+}
+
+func TestTruncateLogQuery(t *testing.T) {
+	long := "SELECT * FROM users WHERE id IN (" + strings.Repeat("?,", 4096) + "?)"
+
+	// without an override the default cap applies
+	assert.Equal(t, "SELECT 1", truncateLogQuery(context.Background(), "SELECT 1"))
+	assert.Equal(t, defaultMaxLogQueryLen+3, len(truncateLogQuery(context.Background(), long)))
+
+	ctx := context.WithValue(context.Background(), maxLogQueryLenKey{}, 10)
+	assert.Equal(t, long[:10]+"...", truncateLogQuery(ctx, long))
+	assert.Equal(t, "SELECT 1", truncateLogQuery(ctx, "SELECT 1"))
+}
+
+func TestMarkLogQueryLen(t *testing.T) {
+	conn := &commonSqlConn{}
+	ctx := conn.markLogQueryLen(context.Background())
+	assert.Nil(t, ctx.Value(maxLogQueryLenKey{}))
+
+	WithMaxLogQueryLen(64)(conn)
+	ctx = conn.markLogQueryLen(context.Background())
+	assert.Equal(t, 64, ctx.Value(maxLogQueryLenKey{}))
+}
+
+func TestFingerprintCapped(t *testing.T) {
+	conn := &commonSqlConn{maxLogQueryLen: 16}
+	long := "SELECT * FROM a_very_long_table_name WHERE name = 'x'"
+	assert.Equal(t, 19, len(conn.fingerprint(long)))
+	assert.True(t, strings.HasSuffix(conn.fingerprint(long), "..."))
+
+	short := &commonSqlConn{}
+	assert.Equal(t, "SELECT * FROM t WHERE name = ?", short.fingerprint("SELECT * FROM t WHERE name = 'x'"))
+}
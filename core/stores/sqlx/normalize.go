@@ -0,0 +1,40 @@
+package sqlx
+
+import "regexp"
+
+// QueryNormalizer rewrites a query into a low-cardinality fingerprint for use
+// as a metric or trace label. The executed SQL is never changed.
+type QueryNormalizer func(query string) string
+
+var (
+	stringLiteralRegex = regexp.MustCompile(`'(?:[^'\\]|\\.)*'`)
+	numberLiteralRegex = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	inListRegex        = regexp.MustCompile(`(?i)\bin\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// NormalizeQuery is the default QueryNormalizer, replacing string and number
+// literals with ? and collapsing IN lists into a single placeholder.
+func NormalizeQuery(query string) string {
+	query = stringLiteralRegex.ReplaceAllString(query, "?")
+	query = numberLiteralRegex.ReplaceAllString(query, "?")
+	return inListRegex.ReplaceAllString(query, "in (?)")
+}
+
+// WithQueryNormalizer customizes the normalizer used to fingerprint queries
+// for trace labels, replacing the default NormalizeQuery.
+func WithQueryNormalizer(normalizer QueryNormalizer) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.normalizer = normalizer
+	}
+}
+
+func (db *commonSqlConn) fingerprint(q string) string {
+	if db.normalizer != nil {
+		q = db.normalizer(q)
+	} else {
+		q = NormalizeQuery(q)
+	}
+
+	// normalization collapses literals but not identifiers, cap the size too
+	return limitQueryLen(q, db.logQueryLimit())
+}
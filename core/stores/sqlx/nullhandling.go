@@ -0,0 +1,109 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// NullHandling controls what happens when a NULL column is scanned into a
+// struct field.
+type NullHandling int
+
+const (
+	// NullDefault keeps the driver's behavior, which may zero the field or
+	// error depending on the driver and field type.
+	NullDefault NullHandling = iota
+	// NullAsZero scans NULL into the zero value of the field, and sets
+	// pointer fields to nil.
+	NullAsZero
+	// NullAsError fails the scan when NULL arrives for a non-pointer field,
+	// so a NULL price can't silently become 0.
+	NullAsError
+	// NullRequirePointer is like NullAsError, with an error message pointing
+	// at the field that needs to become a pointer.
+	NullRequirePointer
+)
+
+// ErrNullIntoNonNullable is an error that indicates a NULL column was scanned
+// into a non-pointer field under NullAsError or NullRequirePointer.
+var ErrNullIntoNonNullable = errors.New("scanned NULL into non-nullable field")
+
+// WithNullHandling customizes how NULL columns are scanned, see NullHandling
+// for the modes.
+func WithNullHandling(mode NullHandling) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.nullHandling = mode
+	}
+}
+
+// nullZeroScanner scans NULL as the zero value of its destination.
+type nullZeroScanner struct {
+	dest interface{}
+}
+
+func (s *nullZeroScanner) Scan(src interface{}) error {
+	// assignScanValue zeroes the destination on nil already
+	return assignScanValue(s.dest, src)
+}
+
+// nonNullScanner fails the scan when NULL arrives for a non-pointer field.
+type nonNullScanner struct {
+	column string
+	mode   NullHandling
+	dest   interface{}
+}
+
+func (s *nonNullScanner) Scan(src interface{}) error {
+	if src == nil {
+		if s.mode == NullRequirePointer {
+			return fmt.Errorf("%w: column %q needs a pointer field", ErrNullIntoNonNullable, s.column)
+		}
+		return fmt.Errorf("%w: column %q", ErrNullIntoNonNullable, s.column)
+	}
+
+	return assignScanValue(s.dest, src)
+}
+
+// nullPtrScanner sets a pointer field to nil on NULL instead of erroring.
+type nullPtrScanner struct {
+	field reflect.Value
+}
+
+func (s *nullPtrScanner) Scan(src interface{}) error {
+	if src == nil {
+		s.field.Set(reflect.Zero(s.field.Type()))
+		return nil
+	}
+
+	if s.field.IsNil() {
+		s.field.Set(reflect.New(mapping.Deref(s.field.Type())))
+	}
+	return assignScanValue(s.field.Interface(), src)
+}
+
+// wrapNullHandling wraps a scan destination according to the configured NULL
+// mode. In NullDefault mode the destination passes through untouched.
+func wrapNullHandling(column string, field reflect.Value, dest interface{},
+	mode NullHandling) interface{} {
+	switch mode {
+	case NullAsZero:
+		if field.Kind() == reflect.Ptr {
+			return &nullPtrScanner{field: field}
+		}
+		return &nullZeroScanner{dest: dest}
+	case NullAsError, NullRequirePointer:
+		if field.Kind() == reflect.Ptr {
+			return &nullPtrScanner{field: field}
+		}
+		return &nonNullScanner{
+			column: column,
+			mode:   mode,
+			dest:   dest,
+		}
+	default:
+		return dest
+	}
+}
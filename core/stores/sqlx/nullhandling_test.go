@@ -0,0 +1,70 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type nullUser struct {
+	ID    int64   `db:"id"`
+	Name  string  `db:"name"`
+	Email *string `db:"email"`
+}
+
+func TestNullHandlingAsZero(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, nil, nil)
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rows)
+
+		var user nullUser
+		conn := NewSqlConnFromDB(db, WithNullHandling(NullAsZero))
+		assert.Nil(t, conn.QueryRow(&user, "select id, name, email from users where id = 1"))
+		assert.Equal(t, int64(1), user.ID)
+		assert.Empty(t, user.Name)
+		assert.Nil(t, user.Email)
+	})
+}
+
+func TestNullHandlingAsError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, nil, nil)
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rows)
+
+		var user nullUser
+		conn := NewSqlConnFromDB(db, WithNullHandling(NullAsError))
+		err := conn.QueryRow(&user, "select id, name, email from users where id = 1")
+		assert.True(t, errors.Is(err, ErrNullIntoNonNullable))
+		assert.Contains(t, err.Error(), `"name"`)
+	})
+}
+
+func TestNullHandlingAsErrorPointerOk(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, "tom", nil)
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rows)
+
+		var user nullUser
+		conn := NewSqlConnFromDB(db, WithNullHandling(NullAsError))
+		assert.Nil(t, conn.QueryRow(&user, "select id, name, email from users where id = 1"))
+		assert.Equal(t, "tom", user.Name)
+		// NULL into a pointer field stays nil
+		assert.Nil(t, user.Email)
+	})
+}
+
+func TestNullHandlingRequirePointer(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(1, nil, "a@b.c")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rows)
+
+		var user nullUser
+		conn := NewSqlConnFromDB(db, WithNullHandling(NullRequirePointer))
+		err := conn.QueryRow(&user, "select id, name, email from users where id = 1")
+		assert.True(t, errors.Is(err, ErrNullIntoNonNullable))
+		assert.Contains(t, err.Error(), "pointer field")
+	})
+}
@@ -0,0 +1,27 @@
+package sqlx
+
+import "context"
+
+type opNameKey struct{}
+
+// WithOpName tags ctx with the logical operation issuing the queries, like an
+// endpoint or repository method name. Statement logs, and the slow-query log
+// in particular, prefix the SQL with it, so slow queries group by operation
+// instead of raw SQL. The sql spans carry it as the sql.op attribute too.
+func WithOpName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, opNameKey{}, name)
+}
+
+func opNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(opNameKey{}).(string)
+	return name
+}
+
+// prependOpName prefixes stmt with the operation name of ctx, if any.
+func prependOpName(ctx context.Context, stmt string) string {
+	if name := opNameFromContext(ctx); len(name) > 0 {
+		return "[" + name + "] " + stmt
+	}
+
+	return stmt
+}
@@ -0,0 +1,22 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOpName(t *testing.T) {
+	assert.Empty(t, opNameFromContext(context.Background()))
+
+	ctx := WithOpName(context.Background(), "GetUser")
+	assert.Equal(t, "GetUser", opNameFromContext(ctx))
+}
+
+func TestPrependOpName(t *testing.T) {
+	stmt := "select * from users"
+	assert.Equal(t, stmt, prependOpName(context.Background(), stmt))
+	assert.Equal(t, "[GetUser] "+stmt,
+		prependOpName(WithOpName(context.Background(), "GetUser"), stmt))
+}
@@ -0,0 +1,102 @@
+package sqlx
+
+import "context"
+
+type (
+	argsOnErrorKey    struct{}
+	withoutBreakerKey struct{}
+)
+
+// WithoutBreakerCtx returns a context that makes the call on it bypass the
+// circuit breaker, for administrative and health-check queries that must run
+// even while the breaker is open.
+func WithoutBreakerCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutBreakerKey{}, true)
+}
+
+func breakerDisabled(ctx context.Context) bool {
+	disabled, ok := ctx.Value(withoutBreakerKey{}).(bool)
+	return ok && disabled
+}
+
+// ArgValidator defines the method to validate query args before execution.
+type ArgValidator func(query string, args []interface{}) error
+
+// WithArgValidator customizes a SqlConn with given validator, which is invoked
+// before execution to fail fast on invalid args with a clear message,
+// instead of a cryptic driver error.
+func WithArgValidator(validator ArgValidator) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.argValidator = validator
+	}
+}
+
+// WithArgsOnError logs the bound args of a statement only when it fails,
+// keeping them out of the info and slow logs. Use it to keep PII out of
+// routine logs while still being able to reproduce failing queries.
+func WithArgsOnError(on bool) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.argsOnError = on
+	}
+}
+
+func (db *commonSqlConn) markArgsOnError(ctx context.Context) context.Context {
+	if !db.argsOnError {
+		return ctx
+	}
+
+	return context.WithValue(ctx, argsOnErrorKey{}, true)
+}
+
+func argsOnErrorOnly(ctx context.Context) bool {
+	on, ok := ctx.Value(argsOnErrorKey{}).(bool)
+	return ok && on
+}
+
+// WithAcceptDeadlineExceeded makes context.DeadlineExceeded acceptable to the
+// circuit breaker, so client-side timeouts don't open the circuit for a
+// healthy database.
+//
+// Deprecated: deadline exceeded errors are acceptable by default now,
+// this option is a no-op kept for compatibility.
+func WithAcceptDeadlineExceeded() SqlOption {
+	return func(_ *commonSqlConn) {
+	}
+}
+
+// WithValidationQuery validates new connections by running q instead of a
+// protocol ping, for environments like connection poolers where a ping doesn't
+// exercise the actual data path, e.g. `SELECT 1 FROM dual`.
+func WithValidationQuery(q string) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.validationQuery = q
+	}
+}
+
+// WithStrictEnumValidation rejects rows whose scanned enum values fail their
+// type's EnumValidator check, returning ErrInvalidEnumValue at scan time.
+func WithStrictEnumValidation() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.strictEnum = true
+	}
+}
+
+// WithStrictColumns makes the non-partial query methods error on result
+// columns that map to no struct field, instead of silently discarding them.
+// Without it the non-partial methods only require every struct field to be
+// filled: missing columns error, extra columns are discarded. The Partial
+// variants additionally tolerate missing columns, leaving those fields zero.
+func WithStrictColumns() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.strictColumns = true
+	}
+}
+
+// WithCaseInsensitiveColumns matches result columns to db tags case-insensitively,
+// for databases that fold column names to uppercase. Default stays case-sensitive
+// to avoid the extra per-row cost.
+func WithCaseInsensitiveColumns() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.scanCaseInsensitive = true
+	}
+}
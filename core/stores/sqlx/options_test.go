@@ -0,0 +1,85 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func TestWithArgValidator(t *testing.T) {
+	errBadArg := errors.New("bad arg")
+	conn := NewMysql(mockedDatasource, WithArgValidator(func(query string, args []interface{}) error {
+		for _, arg := range args {
+			if arg == nil {
+				return errBadArg
+			}
+		}
+		return nil
+	}))
+
+	_, err := conn.Exec("any", nil)
+	assert.Equal(t, errBadArg, err)
+
+	var val string
+	assert.Equal(t, errBadArg, conn.QueryRow(&val, "any", nil))
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "select * from users where name = ?",
+		NormalizeQuery("select * from users where name = 'kevin'"))
+	assert.Equal(t, "select * from users where id = ?",
+		NormalizeQuery("select * from users where id = 42"))
+	assert.Equal(t, "select * from users where id in (?)",
+		NormalizeQuery("select * from users where id in (?, ?, ?)"))
+}
+
+func TestWithQueryNormalizer(t *testing.T) {
+	conn := NewSqlConn("mysql", mockedDatasource, WithQueryNormalizer(func(string) string {
+		return "custom"
+	})).(*commonSqlConn)
+	assert.Equal(t, "custom", conn.fingerprint("select * from users"))
+
+	plain := NewSqlConn("mysql", mockedDatasource).(*commonSqlConn)
+	assert.Equal(t, "select ?", plain.fingerprint("select 1"))
+}
+
+func TestWithArgsOnError(t *testing.T) {
+	conn := NewSqlConn("mysql", mockedDatasource, WithArgsOnError(true)).(*commonSqlConn)
+	ctx := conn.markArgsOnError(context.Background())
+	assert.True(t, argsOnErrorOnly(ctx))
+
+	plain := NewSqlConn("mysql", mockedDatasource).(*commonSqlConn)
+	ctx = plain.markArgsOnError(context.Background())
+	assert.False(t, argsOnErrorOnly(ctx))
+}
+
+func TestWithoutBreakerCtx(t *testing.T) {
+	assert.False(t, breakerDisabled(context.Background()))
+	assert.True(t, breakerDisabled(WithoutBreakerCtx(context.Background())))
+}
+
+func TestAcceptableDeadlineExceeded(t *testing.T) {
+	conn := NewMysql(mockedDatasource)
+	db, err := asCommonSqlConn(conn)
+	assert.Nil(t, err)
+	assert.True(t, db.acceptable(context.DeadlineExceeded))
+	assert.True(t, db.acceptable(fmt.Errorf("query failed: %w", context.DeadlineExceeded)))
+}
+
+func TestBreakerIgnoresDeadlineExceeded(t *testing.T) {
+	conn := NewMysql(mockedDatasource)
+	db, err := asCommonSqlConn(conn)
+	assert.Nil(t, err)
+
+	// a flood of client timeouts must not open the circuit
+	for i := 0; i < 1000; i++ {
+		err := db.brk.DoWithAcceptable(func() error {
+			return context.DeadlineExceeded
+		}, db.acceptable)
+		assert.NotEqual(t, breaker.ErrServiceUnavailable, err)
+	}
+}
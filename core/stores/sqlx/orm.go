@@ -1,8 +1,13 @@
 package sqlx
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/zeromicro/go-zero/core/mapping"
@@ -10,6 +15,51 @@ import (
 
 const tagName = "db"
 
+// RowScanner lets a destination type take over scanning of its rows,
+// as an escape hatch for computed fields, custom null handling or
+// performance-critical paths. ScanRow is called once per row, and must
+// not advance the rows iterator.
+type RowScanner interface {
+	ScanRow(rows *sql.Rows) error
+}
+
+var rowScannerType = reflect.TypeOf((*RowScanner)(nil)).Elem()
+
+// scanErrIndexRegex extracts the failing column index from database/sql scan errors.
+var scanErrIndexRegex = regexp.MustCompile(`column index (\d+)`)
+
+type columnTypeProvider interface {
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+// wrapScanError enriches a scan failure with the column name, the database
+// type and the destination Go type, so conversion failures are diagnosable
+// without guessing which column was involved.
+func wrapScanError(err error, scanner rowsScanner, columns []string, values []interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	matches := scanErrIndexRegex.FindStringSubmatch(err.Error())
+	if len(matches) < 2 {
+		return err
+	}
+
+	idx, convErr := strconv.Atoi(matches[1])
+	if convErr != nil || idx < 0 || idx >= len(columns) || idx >= len(values) {
+		return err
+	}
+
+	dbType := "unknown"
+	if provider, ok := scanner.(columnTypeProvider); ok {
+		if types, typesErr := provider.ColumnTypes(); typesErr == nil && idx < len(types) {
+			dbType = types[idx].DatabaseTypeName()
+		}
+	}
+
+	return fmt.Errorf("scan column %q (%s) into %T: %w", columns[idx], dbType, values[idx], err)
+}
+
 var (
 	// ErrNotMatchDestination is an error that indicates not matching destination to scan.
 	ErrNotMatchDestination = errors.New("not matching destination to scan")
@@ -19,8 +69,47 @@ var (
 	ErrNotSettable = errors.New("passed in variable is not settable")
 	// ErrUnsupportedValueType is an error that indicates unsupported unmarshal type.
 	ErrUnsupportedValueType = errors.New("unsupported unmarshal type")
+	// ErrInvalidEnumValue is an error that indicates a scanned enum value is not
+	// one of the values the type declares valid.
+	ErrInvalidEnumValue = errors.New("invalid enum value")
+	// ErrAmbiguousColumn is an error that indicates multiple result columns
+	// share a name that maps to the same struct field, as happens when a join
+	// selects `id` from both tables. Alias the columns to table-qualified
+	// names (`users.id AS "users.id"`) and tag the fields accordingly
+	// (`db:"users.id"`) to disambiguate.
+	ErrAmbiguousColumn = errors.New("ambiguous duplicate column")
 )
 
+// EnumValidator lets enum-like field types declare whether their current value
+// is valid. With WithStrictEnumValidation, fields implementing it are checked
+// after each row is scanned, so unknown values fail at scan time instead of
+// propagating through business logic.
+type EnumValidator interface {
+	Valid() bool
+}
+
+// scanOptions controls how rows are mapped into the destination.
+type scanOptions struct {
+	ctx             context.Context
+	strict          bool
+	strictColumns   bool
+	caseInsensitive bool
+	strictEnum      bool
+	decimalAsString bool
+	nullHandling    NullHandling
+}
+
+// checkContext reports whether the scan deadline passed, so a huge result set
+// can't blow the request budget after a fast-returning query. The rows scanned
+// so far stay in the destination as partial results.
+func (o scanOptions) checkContext() error {
+	if o.ctx == nil {
+		return nil
+	}
+
+	return o.ctx.Err()
+}
+
 type rowsScanner interface {
 	Columns() ([]string, error)
 	Err() error
@@ -28,83 +117,248 @@ type rowsScanner interface {
 	Scan(v ...interface{}) error
 }
 
-func getTaggedFieldValueMap(v reflect.Value) (map[string]interface{}, error) {
+// nullableGroup tracks the columns of an embedded pointer struct, so the pointer
+// can be reset to nil after scanning when all of its columns were NULL.
+type nullableGroup struct {
+	field    reflect.Value
+	scanners []*nullableScanner
+}
+
+func (g *nullableGroup) finalize() {
+	for _, scanner := range g.scanners {
+		if scanner.valid {
+			return
+		}
+	}
+
+	g.field.Set(reflect.Zero(g.field.Type()))
+}
+
+// nullableScanner wraps a destination of an embedded pointer struct field,
+// recording whether the column was NULL.
+type nullableScanner struct {
+	column string
+	dest   interface{}
+	valid  bool
+}
+
+func (s *nullableScanner) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	s.valid = true
+	return assignScanValue(s.dest, src)
+}
+
+func getTaggedFieldValueMap(v reflect.Value, opts scanOptions) (
+	map[string]interface{}, []*nullableGroup, error) {
 	rt := mapping.Deref(v.Type())
 	size := rt.NumField()
 	result := make(map[string]interface{}, size)
+	var groups []*nullableGroup
 
+	var anonymous []int
 	for i := 0; i < size; i++ {
-		key := parseTagName(rt.Field(i))
+		structField := rt.Field(i)
+		key := parseTagName(structField)
 		if len(key) == 0 {
-			return nil, nil
+			if structField.Anonymous && mapping.Deref(structField.Type).Kind() == reflect.Struct {
+				anonymous = append(anonymous, i)
+				continue
+			}
+			return nil, nil, nil
 		}
 
 		valueField := reflect.Indirect(v).Field(i)
 		switch valueField.Kind() {
 		case reflect.Ptr:
 			if !valueField.CanInterface() {
-				return nil, ErrNotReadableValue
+				return nil, nil, ErrNotReadableValue
 			}
 			if valueField.IsNil() {
 				baseValueType := mapping.Deref(valueField.Type())
 				valueField.Set(reflect.New(baseValueType))
 			}
-			result[key] = valueField.Interface()
+			result[key] = wrapNullHandling(key, valueField, valueField.Interface(), opts.nullHandling)
 		default:
 			if !valueField.CanAddr() || !valueField.Addr().CanInterface() {
-				return nil, ErrNotReadableValue
+				return nil, nil, ErrNotReadableValue
+			}
+			result[key] = wrapNullHandling(key, valueField, valueField.Addr().Interface(), opts.nullHandling)
+		}
+	}
+
+	for _, i := range anonymous {
+		valueField := reflect.Indirect(v).Field(i)
+		nullable := valueField.Kind() == reflect.Ptr
+		if nullable && valueField.IsNil() {
+			valueField.Set(reflect.New(mapping.Deref(valueField.Type())))
+		}
+
+		inner, innerGroups, err := getTaggedFieldValueMap(reflect.Indirect(valueField), opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if inner == nil {
+			// untagged fields inside the embedded struct, fall back to positional mapping
+			return nil, nil, nil
+		}
+
+		group := &nullableGroup{field: valueField}
+		for key, dest := range inner {
+			// outer fields win on column name collisions
+			if _, ok := result[key]; ok {
+				continue
+			}
+
+			if nullable {
+				scanner := &nullableScanner{
+					column: key,
+					dest:   dest,
+				}
+				group.scanners = append(group.scanners, scanner)
+				result[key] = scanner
+			} else {
+				result[key] = dest
 			}
-			result[key] = valueField.Addr().Interface()
 		}
+		if len(group.scanners) > 0 {
+			groups = append(groups, group)
+		}
+		groups = append(groups, innerGroups...)
 	}
 
-	return result, nil
+	return result, groups, nil
 }
 
-func mapStructFieldsIntoSlice(v reflect.Value, columns []string, strict bool) ([]interface{}, error) {
+func mapStructFieldsIntoSlice(v reflect.Value, columns []string, opts scanOptions) (
+	[]interface{}, func(), error) {
 	fields := unwrapFields(v)
-	if strict && len(columns) < len(fields) {
-		return nil, ErrNotMatchDestination
+	if opts.strict && len(columns) < len(fields) {
+		return nil, nil, ErrNotMatchDestination
 	}
 
-	taggedMap, err := getTaggedFieldValueMap(v)
+	taggedMap, groups, err := getTaggedFieldValueMap(v, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	values := make([]interface{}, len(columns))
 	if len(taggedMap) == 0 {
 		for i := 0; i < len(values); i++ {
+			if i >= len(fields) {
+				// extra columns beyond the struct fields are discarded
+				if opts.strict && opts.strictColumns {
+					return nil, nil, fmt.Errorf("%w: unmapped column %q",
+						ErrNotMatchDestination, columns[i])
+				}
+				var anonymous interface{}
+				values[i] = &anonymous
+				continue
+			}
+
 			valueField := fields[i]
 			switch valueField.Kind() {
 			case reflect.Ptr:
 				if !valueField.CanInterface() {
-					return nil, ErrNotReadableValue
+					return nil, nil, ErrNotReadableValue
 				}
 				if valueField.IsNil() {
 					baseValueType := mapping.Deref(valueField.Type())
 					valueField.Set(reflect.New(baseValueType))
 				}
-				values[i] = valueField.Interface()
+				values[i] = wrapNullHandling(columns[i], valueField, valueField.Interface(), opts.nullHandling)
 			default:
 				if !valueField.CanAddr() || !valueField.Addr().CanInterface() {
-					return nil, ErrNotReadableValue
+					return nil, nil, ErrNotReadableValue
 				}
-				values[i] = valueField.Addr().Interface()
+				values[i] = wrapNullHandling(columns[i], valueField, valueField.Addr().Interface(), opts.nullHandling)
 			}
 		}
 	} else {
+		if opts.caseInsensitive {
+			lowered := make(map[string]interface{}, len(taggedMap))
+			for key, value := range taggedMap {
+				lowered[strings.ToLower(key)] = value
+			}
+			taggedMap = lowered
+		}
+		matched := make(map[string]bool, len(columns))
 		for i, column := range columns {
+			if opts.caseInsensitive {
+				column = strings.ToLower(column)
+			}
 			if tagged, ok := taggedMap[column]; ok {
+				// two columns scanning into one field would silently keep the
+				// last value, refuse instead of corrupting joined results
+				if matched[column] {
+					return nil, nil, fmt.Errorf("%w: %q", ErrAmbiguousColumn, columns[i])
+				}
+				matched[column] = true
 				values[i] = tagged
 			} else {
+				if opts.strict && opts.strictColumns {
+					return nil, nil, fmt.Errorf("%w: unmapped column %q",
+						ErrNotMatchDestination, column)
+				}
 				var anonymous interface{}
 				values[i] = &anonymous
 			}
 		}
 	}
 
-	return values, nil
+	finalize := func() {
+		for _, group := range groups {
+			group.finalize()
+		}
+	}
+
+	return values, finalize, nil
+}
+
+// validateEnums checks all fields implementing EnumValidator, recursing into
+// embedded structs. Nil pointer fields are skipped, they carry no value.
+func validateEnums(v reflect.Value) error {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := v.Type().Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+		}
+
+		if structField.Anonymous {
+			if err := validateEnums(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		candidate := field
+		if field.Kind() != reflect.Ptr {
+			if !field.CanAddr() {
+				continue
+			}
+			candidate = field.Addr()
+		}
+		if !candidate.CanInterface() {
+			continue
+		}
+
+		if validator, ok := candidate.Interface().(EnumValidator); ok && !validator.Valid() {
+			return fmt.Errorf("%w: field %s = %v", ErrInvalidEnumValue,
+				structField.Name, reflect.Indirect(field).Interface())
+		}
+	}
+
+	return nil
 }
 
 func parseTagName(field reflect.StructField) string {
@@ -118,6 +372,10 @@ func parseTagName(field reflect.StructField) string {
 }
 
 func unmarshalRow(v interface{}, scanner rowsScanner, strict bool) error {
+	return unmarshalRowWithOptions(v, scanner, scanOptions{strict: strict})
+}
+
+func unmarshalRowWithOptions(v interface{}, scanner rowsScanner, opts scanOptions) error {
 	if !scanner.Next() {
 		if err := scanner.Err(); err != nil {
 			return err
@@ -125,6 +383,12 @@ func unmarshalRow(v interface{}, scanner rowsScanner, strict bool) error {
 		return ErrNotFound
 	}
 
+	if rs, ok := v.(RowScanner); ok {
+		if rows, ok := scanner.(*sql.Rows); ok {
+			return rs.ScanRow(rows)
+		}
+	}
+
 	rv := reflect.ValueOf(v)
 	if err := mapping.ValidatePtr(&rv); err != nil {
 		return err
@@ -149,18 +413,33 @@ func unmarshalRow(v interface{}, scanner rowsScanner, strict bool) error {
 			return err
 		}
 
-		values, err := mapStructFieldsIntoSlice(rve, columns, strict)
+		values, finalize, err := mapStructFieldsIntoSlice(rve, columns, opts)
 		if err != nil {
 			return err
 		}
 
-		return scanner.Scan(values...)
+		if opts.decimalAsString {
+			wrapDecimalValues(scanner, values)
+		}
+		if err := scanner.Scan(values...); err != nil {
+			return wrapScanError(err, scanner, columns, values)
+		}
+
+		finalize()
+		if opts.strictEnum {
+			return validateEnums(rve)
+		}
+		return nil
 	default:
 		return ErrUnsupportedValueType
 	}
 }
 
 func unmarshalRows(v interface{}, scanner rowsScanner, strict bool) error {
+	return unmarshalRowsWithOptions(v, scanner, scanOptions{strict: strict})
+}
+
+func unmarshalRowsWithOptions(v interface{}, scanner rowsScanner, opts scanOptions) error {
 	rv := reflect.ValueOf(v)
 	if err := mapping.ValidatePtr(&rv); err != nil {
 		return err
@@ -193,17 +472,61 @@ func unmarshalRows(v interface{}, scanner rowsScanner, strict bool) error {
 			}
 
 			base := mapping.Deref(rte.Elem())
+			if rows, ok := scanner.(*sql.Rows); ok && reflect.PtrTo(base).Implements(rowScannerType) {
+				for rows.Next() {
+					if err := opts.checkContext(); err != nil {
+						return err
+					}
+
+					value := reflect.New(base)
+					if err := value.Interface().(RowScanner).ScanRow(rows); err != nil {
+						return err
+					}
+
+					appendFn(value)
+				}
+
+				return rows.Err()
+			}
+
 			switch base.Kind() {
 			case reflect.Bool,
 				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 				reflect.Float32, reflect.Float64,
 				reflect.String:
+				columns, err := scanner.Columns()
+				if err != nil {
+					return err
+				}
+				if len(columns) > 1 && opts.strict {
+					return ErrNotMatchDestination
+				}
+
 				for scanner.Next() {
+					if err := opts.checkContext(); err != nil {
+						return err
+					}
+
 					value := reflect.New(base)
-					if err := fillFn(value.Interface()); err != nil {
+					if len(columns) <= 1 {
+						if err := fillFn(value.Interface()); err != nil {
+							return err
+						}
+						continue
+					}
+
+					// scan the first column into the slice, discard the rest
+					values := make([]interface{}, len(columns))
+					values[0] = value.Interface()
+					for i := 1; i < len(values); i++ {
+						var anonymous interface{}
+						values[i] = &anonymous
+					}
+					if err := scanner.Scan(values...); err != nil {
 						return err
 					}
+					appendFn(value)
 				}
 			case reflect.Struct:
 				columns, err := scanner.Columns()
@@ -212,16 +535,29 @@ func unmarshalRows(v interface{}, scanner rowsScanner, strict bool) error {
 				}
 
 				for scanner.Next() {
+					if err := opts.checkContext(); err != nil {
+						return err
+					}
+
 					value := reflect.New(base)
-					values, err := mapStructFieldsIntoSlice(value, columns, strict)
+					values, finalize, err := mapStructFieldsIntoSlice(value, columns, opts)
 					if err != nil {
 						return err
 					}
 
+					if opts.decimalAsString {
+						wrapDecimalValues(scanner, values)
+					}
 					if err := scanner.Scan(values...); err != nil {
-						return err
+						return wrapScanError(err, scanner, columns, values)
 					}
 
+					finalize()
+					if opts.strictEnum {
+						if err := validateEnums(value); err != nil {
+							return err
+						}
+					}
 					appendFn(value)
 				}
 			default:
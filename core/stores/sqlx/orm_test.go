@@ -869,6 +869,147 @@ func TestUnmarshalRowsStructAndEmbeddedStructPtrAnonymousWithTags(t *testing.T)
 	})
 }
 
+func TestUnmarshalRowsEmbeddedBaseModel(t *testing.T) {
+	type BaseModel struct {
+		Id        int64  `db:"id"`
+		CreatedAt string `db:"created_at"`
+		UpdatedAt string `db:"updated_at"`
+	}
+	var value []struct {
+		BaseModel
+		Name string `db:"name"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "name"}).
+			FromCSVString("1,2020-01-01,2020-01-02,first")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRows(&value, rows, true)
+		}, "select id, created_at, updated_at, name from users"))
+
+		assert.Equal(t, int64(1), value[0].Id)
+		assert.Equal(t, "2020-01-01", value[0].CreatedAt)
+		assert.Equal(t, "2020-01-02", value[0].UpdatedAt)
+		assert.Equal(t, "first", value[0].Name)
+	})
+}
+
+func TestUnmarshalRowsEmbeddedColumnCollisionOuterWins(t *testing.T) {
+	type Embed struct {
+		Name string `db:"name"`
+	}
+	var value []struct {
+		Embed
+		Name string `db:"name"`
+		Age  int64  `db:"age"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "age"}).FromCSVString("first,2")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRows(&value, rows, false)
+		}, "select name, age from users"))
+
+		assert.Equal(t, "first", value[0].Name)
+		assert.Empty(t, value[0].Embed.Name)
+		assert.Equal(t, int64(2), value[0].Age)
+	})
+}
+
+func TestUnmarshalRowEmbeddedPtrCollisionOuterWins(t *testing.T) {
+	type Embed struct {
+		Name  string `db:"name"`
+		Value int64  `db:"value"`
+	}
+	var value struct {
+		*Embed
+		Name string `db:"name"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "value"}).FromCSVString("first,3")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRow(&value, rows, false)
+		}, "select name, value from users"))
+
+		assert.Equal(t, "first", value.Name)
+		assert.Equal(t, int64(3), value.Embed.Value)
+		assert.Empty(t, value.Embed.Name)
+	})
+}
+
+func TestUnmarshalRowExtraColumnsFillAllFields(t *testing.T) {
+	var value struct {
+		Name string `db:"name"`
+		Age  int64  `db:"age"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "age", "extra"}).FromCSVString("first,2,ignored")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		// the non-partial variant tolerates extra columns, every field is filled
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRow(&value, rows, true)
+		}, "select name, age, extra from users"))
+
+		assert.Equal(t, "first", value.Name)
+		assert.Equal(t, int64(2), value.Age)
+	})
+}
+
+func TestUnmarshalRowStrictColumnsRejectsExtra(t *testing.T) {
+	var value struct {
+		Name string `db:"name"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "extra"}).FromCSVString("first,ignored")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		err := query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRowWithOptions(&value, rows, scanOptions{
+				strict:        true,
+				strictColumns: true,
+			})
+		}, "select name, extra from users")
+		assert.ErrorIs(t, err, ErrNotMatchDestination)
+	})
+}
+
+func TestUnmarshalRowUntaggedExtraColumns(t *testing.T) {
+	var value struct {
+		Name string
+		Age  int64
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "age", "extra"}).FromCSVString("first,2,ignored")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRow(&value, rows, true)
+		}, "select name, age, extra from users"))
+
+		assert.Equal(t, "first", value.Name)
+		assert.Equal(t, int64(2), value.Age)
+	})
+}
+
+func TestQueryRowWithStrictColumns(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "extra"}).FromCSVString("first,ignored")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var value struct {
+			Name string `db:"name"`
+		}
+		conn := NewSqlConnFromDB(db, WithStrictColumns())
+		err := conn.QueryRow(&value, "select name, extra from users")
+		assert.ErrorIs(t, err, ErrNotMatchDestination)
+	})
+}
+
 func TestUnmarshalRowsStructPtr(t *testing.T) {
 	expect := []*struct {
 		Name string
@@ -1083,3 +1224,267 @@ func (m *mockedScanner) Next() bool {
 func (m *mockedScanner) Scan(v ...interface{}) error {
 	return m.scanErr
 }
+
+func TestUnmarshalRowsNullableEmbeddedStruct(t *testing.T) {
+	type Customer struct {
+		CustomerName string `db:"customer_name"`
+	}
+	type order struct {
+		*Customer
+		Id int64 `db:"id"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "customer_name"}).
+			AddRow(1, "kevin").
+			AddRow(2, nil)
+		mock.ExpectQuery("select (.+) from orders").WillReturnRows(rs)
+
+		var orders []order
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRows(&orders, rows, true)
+		}, "select id, customer_name from orders"))
+		assert.Equal(t, 2, len(orders))
+		assert.Equal(t, int64(1), orders[0].Id)
+		if assert.NotNil(t, orders[0].Customer) {
+			assert.Equal(t, "kevin", orders[0].CustomerName)
+		}
+		assert.Equal(t, int64(2), orders[1].Id)
+		assert.Nil(t, orders[1].Customer)
+	})
+}
+
+func TestUnmarshalRowsCaseInsensitiveColumns(t *testing.T) {
+	type user struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"ID", "NAME"}).AddRow(1, "kevin")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var users []user
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRowsWithOptions(&users, rows, scanOptions{
+				strict:          true,
+				caseInsensitive: true,
+			})
+		}, "select id, name from users"))
+		assert.Equal(t, []user{{Id: 1, Name: "kevin"}}, users)
+	})
+}
+
+type scannedUser struct {
+	name  string
+	extra string
+}
+
+func (u *scannedUser) ScanRow(rows *sql.Rows) error {
+	if err := rows.Scan(&u.name); err != nil {
+		return err
+	}
+
+	u.extra = "computed:" + u.name
+	return nil
+}
+
+func TestUnmarshalRowRowScanner(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name"}).FromCSVString("first")
+		mock.ExpectQuery("select (.+) from users where user_id = ?").
+			WithArgs("1").WillReturnRows(rs)
+
+		var r scannedUser
+		conn := NewSqlConnFromDB(db)
+		err := conn.QueryRow(&r, `select name from users where user_id = ?`, "1")
+		assert.Nil(t, err)
+		assert.Equal(t, "first", r.name)
+		assert.Equal(t, "computed:first", r.extra)
+	})
+}
+
+func TestUnmarshalRowsRowScanner(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name"}).FromCSVString("first\nsecond")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var rows []scannedUser
+		conn := NewSqlConnFromDB(db)
+		err := conn.QueryRows(&rows, `select name from users`)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []scannedUser{
+			{name: "first", extra: "computed:first"},
+			{name: "second", extra: "computed:second"},
+		}, rows)
+	})
+}
+
+func TestUnmarshalRowsPrimitiveSliceFirstColumn(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,first\n2,second")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var ids []int64
+		conn := NewSqlConnFromDB(db)
+		err := conn.QueryRowsPartial(&ids, "select id, name from users")
+		assert.Nil(t, err)
+		assert.EqualValues(t, []int64{1, 2}, ids)
+	})
+}
+
+func TestUnmarshalRowsPrimitiveSliceStrictColumnMismatch(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,first")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var ids []int64
+		conn := NewSqlConnFromDB(db)
+		assert.Equal(t, ErrNotMatchDestination,
+			conn.QueryRows(&ids, "select id, name from users"))
+	})
+}
+
+func TestUnmarshalRowScanErrorColumnContext(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name", "amount"}).FromCSVString("kevin,not-a-number")
+		mock.ExpectQuery("select (.+) from orders").WillReturnRows(rs)
+
+		var r struct {
+			Name   string `db:"name"`
+			Amount int64  `db:"amount"`
+		}
+		conn := NewSqlConnFromDB(db)
+		err := conn.QueryRow(&r, "select name, amount from orders limit 1")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `scan column "amount"`)
+		assert.Contains(t, err.Error(), "*int64")
+	})
+}
+
+type orderStatus string
+
+func (s orderStatus) Valid() bool {
+	switch s {
+	case "pending", "paid", "shipped":
+		return true
+	default:
+		return false
+	}
+}
+
+func TestUnmarshalRowStrictEnumValidation(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "status"}).FromCSVString("1,paid")
+		mock.ExpectQuery("select (.+) from orders").WillReturnRows(rs)
+
+		var r struct {
+			ID     int64       `db:"id"`
+			Status orderStatus `db:"status"`
+		}
+		conn := NewSqlConnFromDB(db, WithStrictEnumValidation())
+		assert.Nil(t, conn.QueryRow(&r, "select id, status from orders limit 1"))
+		assert.Equal(t, orderStatus("paid"), r.Status)
+	})
+}
+
+func TestUnmarshalRowStrictEnumValidationRejects(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "status"}).FromCSVString("1,bogus")
+		mock.ExpectQuery("select (.+) from orders").WillReturnRows(rs)
+
+		var r struct {
+			ID     int64       `db:"id"`
+			Status orderStatus `db:"status"`
+		}
+		conn := NewSqlConnFromDB(db, WithStrictEnumValidation())
+		err := conn.QueryRow(&r, "select id, status from orders limit 1")
+		assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+	})
+}
+
+// cancelingScanner cancels its context after the first row, simulating a scan
+// that outlives the request deadline.
+type cancelingScanner struct {
+	rows   []string
+	idx    int
+	cancel context.CancelFunc
+}
+
+func (s *cancelingScanner) Columns() ([]string, error) {
+	return []string{"name"}, nil
+}
+
+func (s *cancelingScanner) Err() error {
+	return nil
+}
+
+func (s *cancelingScanner) Next() bool {
+	if s.idx == 1 {
+		s.cancel()
+	}
+	return s.idx < len(s.rows)
+}
+
+func (s *cancelingScanner) Scan(v ...interface{}) error {
+	*(v[0].(*string)) = s.rows[s.idx]
+	s.idx++
+	return nil
+}
+
+func TestUnmarshalRowsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scanner := &cancelingScanner{
+		rows:   []string{"first", "second"},
+		cancel: cancel,
+	}
+	var vals []string
+	err := unmarshalRowsWithOptions(&vals, scanner, scanOptions{ctx: ctx})
+	assert.Equal(t, context.Canceled, err)
+	// the rows scanned before the deadline stay as partial results
+	assert.EqualValues(t, []string{"first"}, vals)
+}
+
+func TestUnmarshalRowsNoContext(t *testing.T) {
+	scanner := &cancelingScanner{
+		rows:   []string{"first", "second"},
+		cancel: func() {},
+	}
+	var vals []string
+	assert.Nil(t, unmarshalRowsWithOptions(&vals, scanner, scanOptions{}))
+	assert.EqualValues(t, []string{"first", "second"}, vals)
+}
+
+func TestUnmarshalRowStructAmbiguousColumns(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"id", "id"}).FromCSVString("1,2")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var value struct {
+			ID int64 `db:"id"`
+		}
+		err := query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRow(&value, rows, true)
+		}, "select users.id, orders.id from users join orders on users.id = orders.user_id")
+		assert.ErrorIs(t, err, ErrAmbiguousColumn)
+	})
+}
+
+func TestUnmarshalRowStructQualifiedColumns(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"users.id", "orders.id"}).FromCSVString("1,2")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var value struct {
+			UserID  int64 `db:"users.id"`
+			OrderID int64 `db:"orders.id"`
+		}
+		assert.Nil(t, query(context.Background(), db, func(rows *sql.Rows) error {
+			return unmarshalRow(&value, rows, true)
+		}, `select users.id as "users.id", orders.id as "orders.id" from users join orders on users.id = orders.user_id`))
+		assert.EqualValues(t, 1, value.UserID)
+		assert.EqualValues(t, 2, value.OrderID)
+	})
+}
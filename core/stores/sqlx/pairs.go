@@ -0,0 +1,72 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrPairColumns is an error that indicates the pair query didn't return
+// exactly two columns.
+var ErrPairColumns = errors.New("pair query must return exactly two columns")
+
+// QueryPairs runs a two-column query into a map, like QueryPairsCtx.
+func QueryPairs(conn SqlConn, m interface{}, query string, args ...interface{}) error {
+	return QueryPairsCtx(context.Background(), conn, m, query, args...)
+}
+
+// QueryPairsCtx runs a lookup query like `SELECT id, name FROM ...` and scans
+// the rows into m, which must be a pointer to a map, using the first column as
+// key and the second as value. The query goes through the normal read routing.
+func QueryPairsCtx(ctx context.Context, conn SqlConn, m interface{}, query string,
+	args ...interface{}) error {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	return db.queryRows(ctx, func(rows *sql.Rows) error {
+		return scanPairs(m, rows)
+	}, query, args...)
+}
+
+func scanPairs(m interface{}, rows *sql.Rows) error {
+	rv := reflect.ValueOf(m)
+	if err := mapping.ValidatePtr(&rv); err != nil {
+		return err
+	}
+
+	mv := rv.Elem()
+	if mv.Kind() != reflect.Map {
+		return ErrUnsupportedValueType
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 2 {
+		return ErrPairColumns
+	}
+
+	if mv.IsNil() {
+		mv.Set(reflect.MakeMap(mv.Type()))
+	}
+
+	keyType := mv.Type().Key()
+	valueType := mv.Type().Elem()
+	for rows.Next() {
+		key := reflect.New(keyType)
+		value := reflect.New(valueType)
+		if err := rows.Scan(key.Interface(), value.Interface()); err != nil {
+			return err
+		}
+
+		mv.SetMapIndex(key.Elem(), value.Elem())
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,48 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPairs(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,first\n2,second"))
+
+		var m map[int64]string
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, QueryPairs(conn, &m, "select id, name from users"))
+		assert.EqualValues(t, map[int64]string{1: "first", 2: "second"}, m)
+	})
+}
+
+func TestQueryPairsWrongColumnCount(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "age"}).FromCSVString("1,first,10"))
+
+		var m map[int64]string
+		conn := NewSqlConnFromDB(db)
+		assert.Equal(t, ErrPairColumns, QueryPairs(conn, &m, "select id, name, age from users"))
+	})
+}
+
+func TestQueryPairsNotMap(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).FromCSVString("1,first"))
+
+		var s []string
+		conn := NewSqlConnFromDB(db)
+		assert.Equal(t, ErrUnsupportedValueType, QueryPairs(conn, &s, "select id, name from users"))
+	})
+}
+
+func TestQueryPairsUnsupportedConn(t *testing.T) {
+	var m map[int64]string
+	assert.Equal(t, ErrUnsupportedConn, QueryPairs(new(mockedConn), &m, "select id, name from users"))
+}
@@ -0,0 +1,167 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// A PreparedPool lazily prepares and caches one *sql.Stmt per query per
+// datasource, handing the shared statement out to concurrent callers.
+// *sql.Stmt is safe for concurrent use, so hot repeated queries skip the
+// prepare round-trip without each caller managing statement lifecycle.
+// Statements hitting driver.ErrBadConn are re-prepared transparently.
+type PreparedPool struct {
+	db    *commonSqlConn
+	lock  sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+// NewPreparedPool returns a PreparedPool on given conn.
+func NewPreparedPool(conn SqlConn) (*PreparedPool, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedPool{
+		db:    db,
+		stmts: make(map[string]*sql.Stmt),
+	}, nil
+}
+
+// Close closes all cached statements.
+func (p *PreparedPool) Close() error {
+	p.lock.Lock()
+	stmts := p.stmts
+	p.stmts = make(map[string]*sql.Stmt)
+	p.lock.Unlock()
+
+	var lastErr error
+	for _, stmt := range stmts {
+		if err := stmt.Close(); err != nil {
+			lastErr = err
+		}
+		countStmtClosed()
+	}
+
+	return lastErr
+}
+
+// Exec executes q through its cached prepared statement.
+func (p *PreparedPool) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return p.ExecCtx(context.Background(), q, args...)
+}
+
+// ExecCtx executes q through its cached prepared statement.
+func (p *PreparedPool) ExecCtx(ctx context.Context, q string, args ...interface{}) (
+	result sql.Result, err error) {
+	err = p.withStmt(ctx, q, func(stmt *sql.Stmt) error {
+		var execErr error
+		result, execErr = execStmt(ctx, stmt, q, args...)
+		return execErr
+	})
+
+	return
+}
+
+// QueryRow queries one row of q through its cached prepared statement.
+func (p *PreparedPool) QueryRow(v interface{}, q string, args ...interface{}) error {
+	return p.QueryRowCtx(context.Background(), v, q, args...)
+}
+
+// QueryRowCtx queries one row of q through its cached prepared statement.
+func (p *PreparedPool) QueryRowCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	return p.withStmt(ctx, q, func(stmt *sql.Stmt) error {
+		return queryStmt(ctx, stmt, func(rows *sql.Rows) error {
+			return unmarshalRow(v, rows, true)
+		}, q, args...)
+	})
+}
+
+// QueryRows queries rows of q through its cached prepared statement.
+func (p *PreparedPool) QueryRows(v interface{}, q string, args ...interface{}) error {
+	return p.QueryRowsCtx(context.Background(), v, q, args...)
+}
+
+// QueryRowsCtx queries rows of q through its cached prepared statement.
+func (p *PreparedPool) QueryRowsCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	return p.withStmt(ctx, q, func(stmt *sql.Stmt) error {
+		return queryStmt(ctx, stmt, func(rows *sql.Rows) error {
+			return unmarshalRows(v, rows, true)
+		}, q, args...)
+	})
+}
+
+func (p *PreparedPool) withStmt(ctx context.Context, q string, fn func(*sql.Stmt) error) error {
+	stmt, key, err := p.stmt(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(stmt); errors.Is(err, driver.ErrBadConn) {
+		p.invalidate(key, stmt)
+		if stmt, _, err = p.stmt(ctx, q); err != nil {
+			return err
+		}
+		return fn(stmt)
+	}
+
+	return err
+}
+
+func (p *PreparedPool) stmt(ctx context.Context, q string) (*sql.Stmt, string, error) {
+	datasource, err := p.db.DataSourceResp(q)
+	if err != nil {
+		logInstanceError(datasource, err)
+		return nil, "", err
+	}
+
+	key := datasource + "|" + q
+	p.lock.RLock()
+	stmt, ok := p.stmts[key]
+	p.lock.RUnlock()
+	if ok {
+		countStmtCached()
+		return stmt, key, nil
+	}
+
+	pool, err := p.db.connProv(datasource)
+	if err != nil {
+		p.db.onError(datasource, err)
+		return nil, "", err
+	}
+
+	stmt, err = pool.PrepareContext(ctx, q)
+	if err != nil {
+		return nil, "", err
+	}
+
+	countStmtPrepared()
+	p.lock.Lock()
+	if cached, ok := p.stmts[key]; ok {
+		p.lock.Unlock()
+		stmt.Close()
+		countStmtClosed()
+		return cached, key, nil
+	}
+	p.stmts[key] = stmt
+	p.lock.Unlock()
+
+	return stmt, key, nil
+}
+
+func (p *PreparedPool) invalidate(key string, stmt *sql.Stmt) {
+	p.lock.Lock()
+	if p.stmts[key] == stmt {
+		delete(p.stmts, key)
+		countStmtEvicted()
+	}
+	p.lock.Unlock()
+	stmt.Close()
+	countStmtClosed()
+}
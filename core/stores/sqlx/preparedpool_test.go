@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreparedPoolReusesStmt(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectPrepare("select (.+) from users")
+		mock.ExpectQuery("select (.+) from users").WithArgs("1").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("first"))
+		mock.ExpectQuery("select (.+) from users").WithArgs("2").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("second"))
+
+		pool, err := NewPreparedPool(NewSqlConnFromDB(db))
+		assert.Nil(t, err)
+		defer pool.Close()
+
+		var name string
+		assert.Nil(t, pool.QueryRowCtx(context.Background(), &name,
+			"select name from users where id = ?", "1"))
+		assert.Equal(t, "first", name)
+		// second call reuses the cached statement, no second prepare expected
+		assert.Nil(t, pool.QueryRowCtx(context.Background(), &name,
+			"select name from users where id = ?", "2"))
+		assert.Equal(t, "second", name)
+	})
+}
+
+func TestPreparedPoolExec(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectPrepare("update users")
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		pool, err := NewPreparedPool(NewSqlConnFromDB(db))
+		assert.Nil(t, err)
+		defer pool.Close()
+
+		result, err := pool.ExecCtx(context.Background(),
+			"update users set name = ? where id = ?", "foo", "1")
+		assert.Nil(t, err)
+		affected, err := result.RowsAffected()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), affected)
+	})
+}
+
+func TestPreparedPoolUnsupportedConn(t *testing.T) {
+	_, err := NewPreparedPool(new(mockedConn))
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
@@ -0,0 +1,37 @@
+package sqlx
+
+import "context"
+
+// Priority ranks queries for load shedding under pressure.
+type Priority int
+
+const (
+	// PriorityNormal is the default, queries follow the configured behavior.
+	PriorityNormal Priority = iota
+	// PriorityLow marks queries to shed first under pressure, e.g. analytics.
+	PriorityLow
+	// PriorityHigh marks user-facing queries that should wait for capacity
+	// rather than fail fast.
+	PriorityHigh
+)
+
+type priorityKey struct{}
+
+// WithPriority marks the queries on the returned context with the given
+// priority. Under pressure, low-priority queries are rejected first: they
+// never block on the rate limiter, and they are shed outright during the
+// breaker's recovery slow-start ramp, leaving the scarce capacity to
+// user-facing traffic. High-priority queries wait for a rate-limit token even
+// when WithRateLimitFailFast is configured.
+func WithPriority(ctx context.Context, level Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, level)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	level, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+
+	return level
+}
@@ -0,0 +1,68 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+	"golang.org/x/time/rate"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	assert.Equal(t, PriorityNormal, priorityFromContext(context.Background()))
+	ctx := WithPriority(context.Background(), PriorityLow)
+	assert.Equal(t, PriorityLow, priorityFromContext(ctx))
+	ctx = WithPriority(context.Background(), PriorityHigh)
+	assert.Equal(t, PriorityHigh, priorityFromContext(ctx))
+}
+
+func TestPriorityLowNeverWaitsForRateLimit(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		conn := NewSqlConnFromDB(db, WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)))
+
+		_, err := conn.Exec("update users set name = ? where id = ?", "any", 1)
+		assert.Nil(t, err)
+
+		// no fail-fast configured, but low priority sheds instead of waiting
+		ctx := WithPriority(context.Background(), PriorityLow)
+		_, err = conn.ExecCtx(ctx, "update users set name = ? where id = ?", "any", 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+}
+
+func TestPriorityHighWaitsDespiteFailFast(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		conn := NewSqlConnFromDB(db, WithRateLimiter(rate.NewLimiter(rate.Every(time.Millisecond*10), 1)),
+			WithRateLimitFailFast())
+
+		_, err := conn.Exec("update users set name = ? where id = ?", "any", 1)
+		assert.Nil(t, err)
+
+		// high priority blocks for the next token instead of failing fast
+		ctx := WithPriority(context.Background(), PriorityHigh)
+		_, err = conn.ExecCtx(ctx, "update users set name = ? where id = ?", "any", 1)
+		assert.Nil(t, err)
+	})
+}
+
+func TestPriorityLowShedDuringSlowStart(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := NewSqlConnFromDB(db, WithRecoverySlowStart(time.Hour))
+		common, err := asCommonSqlConn(conn)
+		assert.Nil(t, err)
+		common.slowStart.rampStart = time.Now()
+
+		ctx := WithPriority(context.Background(), PriorityLow)
+		_, err = conn.ExecCtx(ctx, "update users set name = ? where id = ?", "any", 1)
+		assert.ErrorIs(t, err, breaker.ErrServiceUnavailable)
+	})
+}
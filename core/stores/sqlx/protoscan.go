@@ -0,0 +1,178 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ScanProto wraps msg so the QueryRow methods scan the row directly into the
+// proto message, e.g. conn.QueryRowCtx(ctx, ScanProto(&user), query, args...).
+// Columns are matched to scalar proto fields by field name first, falling back
+// to the JSON name, so a `user_name` column fills a `user_name` proto field.
+// Unmatched columns are discarded and NULLs leave the field at its zero value,
+// message, list and map fields are never matched.
+func ScanProto(msg proto.Message) RowScanner {
+	return protoScanner{msg: msg}
+}
+
+type protoScanner struct {
+	msg proto.Message
+}
+
+func (s protoScanner) ScanRow(rows *sql.Rows) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	return scanRowIntoProto(rows, columns, s.msg)
+}
+
+// QueryRowsProto queries rows into proto messages, like QueryRowsProtoCtx.
+func QueryRowsProto(conn SqlConn, prototype proto.Message, query string,
+	args ...interface{}) ([]proto.Message, error) {
+	return QueryRowsProtoCtx(context.Background(), conn, prototype, query, args...)
+}
+
+// QueryRowsProtoCtx runs query and returns one proto message per row, each a
+// fresh instance of prototype's type, with columns mapped by the ScanProto
+// convention. It spares the repository layer the struct-to-proto copying when
+// the service layer speaks protobuf anyway.
+func QueryRowsProtoCtx(ctx context.Context, conn SqlConn, prototype proto.Message,
+	query string, args ...interface{}) ([]proto.Message, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []proto.Message
+	if err := db.queryRows(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			msg := prototype.ProtoReflect().New().Interface()
+			if err := scanRowIntoProto(rows, columns, msg); err != nil {
+				return err
+			}
+			messages = append(messages, msg)
+		}
+
+		return rows.Err()
+	}, query, args...); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+type protoFieldTarget struct {
+	fd     protoreflect.FieldDescriptor
+	holder interface{}
+}
+
+func scanRowIntoProto(rows *sql.Rows, columns []string, msg proto.Message) error {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+
+	values := make([]interface{}, len(columns))
+	targets := make([]protoFieldTarget, 0, len(columns))
+	for i, column := range columns {
+		fd := fields.ByName(protoreflect.Name(column))
+		if fd == nil {
+			fd = fields.ByJSONName(column)
+		}
+		if fd == nil || fd.IsList() || fd.IsMap() || fd.Kind() == protoreflect.MessageKind ||
+			fd.Kind() == protoreflect.GroupKind {
+			var anonymous interface{}
+			values[i] = &anonymous
+			continue
+		}
+
+		holder := protoFieldHolder(fd.Kind())
+		values[i] = holder
+		targets = append(targets, protoFieldTarget{fd: fd, holder: holder})
+	}
+
+	if err := rows.Scan(values...); err != nil {
+		return wrapScanError(err, rows, columns, values)
+	}
+
+	for _, target := range targets {
+		if err := setProtoField(m, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func protoFieldHolder(kind protoreflect.Kind) interface{} {
+	switch kind {
+	case protoreflect.BoolKind:
+		return new(sql.NullBool)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return new(sql.NullFloat64)
+	case protoreflect.StringKind:
+		return new(sql.NullString)
+	case protoreflect.BytesKind:
+		return new([]byte)
+	default:
+		// the remaining scalar kinds are all integral, enums included
+		return new(sql.NullInt64)
+	}
+}
+
+func setProtoField(m protoreflect.Message, target protoFieldTarget) error {
+	fd := target.fd
+
+	switch holder := target.holder.(type) {
+	case *sql.NullBool:
+		if holder.Valid {
+			m.Set(fd, protoreflect.ValueOfBool(holder.Bool))
+		}
+	case *sql.NullFloat64:
+		if !holder.Valid {
+			return nil
+		}
+		if fd.Kind() == protoreflect.FloatKind {
+			m.Set(fd, protoreflect.ValueOfFloat32(float32(holder.Float64)))
+		} else {
+			m.Set(fd, protoreflect.ValueOfFloat64(holder.Float64))
+		}
+	case *sql.NullString:
+		if holder.Valid {
+			m.Set(fd, protoreflect.ValueOfString(holder.String))
+		}
+	case *[]byte:
+		if *holder != nil {
+			m.Set(fd, protoreflect.ValueOfBytes(append([]byte(nil), *holder...)))
+		}
+	case *sql.NullInt64:
+		if !holder.Valid {
+			return nil
+		}
+		switch fd.Kind() {
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			m.Set(fd, protoreflect.ValueOfInt32(int32(holder.Int64)))
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			m.Set(fd, protoreflect.ValueOfInt64(holder.Int64))
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			m.Set(fd, protoreflect.ValueOfUint32(uint32(holder.Int64)))
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			m.Set(fd, protoreflect.ValueOfUint64(uint64(holder.Int64)))
+		case protoreflect.EnumKind:
+			m.Set(fd, protoreflect.ValueOfEnum(protoreflect.EnumNumber(holder.Int64)))
+		default:
+			return fmt.Errorf("%w: proto field %s", ErrUnsupportedValueType, fd.FullName())
+		}
+	}
+
+	return nil
+}
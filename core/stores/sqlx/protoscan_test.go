@@ -0,0 +1,101 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func testUserProto(t *testing.T) *dynamicpb.Message {
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:   proto.String("sqlx_user_test.proto"),
+		Syntax: proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:   proto.String("id"),
+					Number: proto.Int32(1),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+				},
+				{
+					Name:     proto.String("user_name"),
+					JsonName: proto.String("userName"),
+					Number:   proto.Int32(2),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				},
+				{
+					Name:   proto.String("score"),
+					Number: proto.Int32(3),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum(),
+				},
+				{
+					Name:   proto.String("active"),
+					Number: proto.Int32(4),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(),
+				},
+			},
+		}},
+	}
+
+	file, err := protodesc.NewFile(fd, nil)
+	assert.Nil(t, err)
+	return dynamicpb.NewMessage(file.Messages().Get(0))
+}
+
+func protoField(msg proto.Message, name string) interface{} {
+	m := msg.ProtoReflect()
+	return m.Get(m.Descriptor().Fields().ByName(protoreflect.Name(name))).Interface()
+}
+
+func TestScanProto(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT .+ FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_name", "score", "active", "extra"}).
+				AddRow(7, "first", 9.5, true, "ignored"))
+
+		user := testUserProto(t)
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, conn.QueryRow(ScanProto(user),
+			"SELECT id, user_name, score, active, extra FROM users"))
+		assert.Equal(t, int64(7), protoField(user, "id"))
+		assert.Equal(t, "first", protoField(user, "user_name"))
+		assert.Equal(t, 9.5, protoField(user, "score"))
+		assert.Equal(t, true, protoField(user, "active"))
+	})
+}
+
+func TestScanProtoNullLeavesZeroValue(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT .+ FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_name"}).AddRow(7, nil))
+
+		user := testUserProto(t)
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, conn.QueryRow(ScanProto(user), "SELECT id, user_name FROM users"))
+		assert.Equal(t, int64(7), protoField(user, "id"))
+		assert.Equal(t, "", protoField(user, "user_name"))
+	})
+}
+
+func TestQueryRowsProto(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT .+ FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_name"}).
+				AddRow(1, "first").AddRow(2, "second"))
+
+		conn := sampleTestConn(db)
+		users, err := QueryRowsProto(conn, testUserProto(t), "SELECT id, user_name FROM users")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(users))
+		assert.Equal(t, int64(1), protoField(users[0], "id"))
+		assert.Equal(t, "second", protoField(users[1], "user_name"))
+	})
+}
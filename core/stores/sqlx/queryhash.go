@@ -0,0 +1,70 @@
+package sqlx
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+)
+
+// QueryRowsHash scans rows and hashes them, like QueryRowsHashCtx.
+func QueryRowsHash(conn SqlConn, v interface{}, query string, args ...interface{}) (string, error) {
+	return QueryRowsHashCtx(context.Background(), conn, v, query, args...)
+}
+
+// QueryRowsHashCtx scans the rows of query into v like QueryRowsCtx, and
+// additionally returns a deterministic hex-encoded hash of the scanned values,
+// computed while streaming so the result set isn't walked twice. The hash is
+// order-sensitive, the same rows in a different order hash differently. Use it
+// for ETags and change detection without buffering or a second query.
+func QueryRowsHashCtx(ctx context.Context, conn SqlConn, v interface{}, query string,
+	args ...interface{}) (string, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return "", err
+	}
+
+	digest := md5.New()
+	if err := db.queryRows(ctx, func(rows *sql.Rows) error {
+		return unmarshalRowsWithOptions(v, &hashingScanner{
+			Rows:   rows,
+			digest: digest,
+		}, db.scanOptions(ctx, true))
+	}, query, args...); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// hashingScanner feeds every scanned value into digest as the rows stream by.
+type hashingScanner struct {
+	*sql.Rows
+	digest hash.Hash
+}
+
+func (s *hashingScanner) Scan(values ...interface{}) error {
+	if err := s.Rows.Scan(values...); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		writeHashValue(s.digest, value)
+	}
+	// terminate the row, so column/row boundaries can't alias
+	s.digest.Write([]byte{'\n'})
+
+	return nil
+}
+
+func writeHashValue(digest hash.Hash, value interface{}) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		value = rv.Elem().Interface()
+	}
+
+	fmt.Fprintf(digest, "%v|", value)
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsHash(t *testing.T) {
+	type user struct {
+		Name string `db:"name"`
+		Age  int64  `db:"age"`
+	}
+
+	hashOf := func(t *testing.T, csv string) string {
+		var hashed string
+		runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+			rs := sqlmock.NewRows([]string{"name", "age"}).FromCSVString(csv)
+			mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+			var users []user
+			hash, err := QueryRowsHash(NewSqlConnFromDB(db), &users,
+				"select name, age from users")
+			assert.Nil(t, err)
+			hashed = hash
+		})
+		return hashed
+	}
+
+	same := hashOf(t, "first,2\nsecond,3")
+	assert.Equal(t, same, hashOf(t, "first,2\nsecond,3"))
+	// the hash is order-sensitive
+	assert.NotEqual(t, same, hashOf(t, "second,3\nfirst,2"))
+	assert.NotEqual(t, same, hashOf(t, "first,2"))
+}
+
+func TestQueryRowsHashScansRows(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rs := sqlmock.NewRows([]string{"name"}).FromCSVString("first\nsecond")
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(rs)
+
+		var users []struct {
+			Name string `db:"name"`
+		}
+		hash, err := QueryRowsHash(NewSqlConnFromDB(db), &users, "select name from users")
+		assert.Nil(t, err)
+		assert.Len(t, hash, 32)
+		assert.Len(t, users, 2)
+		assert.Equal(t, "second", users[1].Name)
+	})
+}
@@ -0,0 +1,94 @@
+package sqlx
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// FingerprintStat aggregates the executions of one normalized query shape.
+	FingerprintStat struct {
+		Fingerprint string
+		Count       int64
+		Errors      int64
+		Total       time.Duration
+		Max         time.Duration
+	}
+
+	// QueryStatsCollector maintains per-fingerprint aggregates of the queries a
+	// conn executes, giving pg_stat_statements-like visibility from the
+	// application side, attributable per connection, without DB privileges.
+	QueryStatsCollector struct {
+		lock  sync.Mutex
+		stats map[string]*FingerprintStat
+	}
+)
+
+// NewQueryStatsCollector returns a QueryStatsCollector.
+func NewQueryStatsCollector() *QueryStatsCollector {
+	return &QueryStatsCollector{
+		stats: make(map[string]*FingerprintStat),
+	}
+}
+
+// QueryStats returns a copy of the aggregates collected so far, most executed
+// first.
+func (c *QueryStatsCollector) QueryStats() []FingerprintStat {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := make([]FingerprintStat, 0, len(c.stats))
+	for _, stat := range c.stats {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Count > stats[j].Count
+	})
+
+	return stats
+}
+
+// Reset drops the collected aggregates.
+func (c *QueryStatsCollector) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stats = make(map[string]*FingerprintStat)
+}
+
+func (c *QueryStatsCollector) observe(fingerprint string, duration time.Duration, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stat, ok := c.stats[fingerprint]
+	if !ok {
+		stat = &FingerprintStat{Fingerprint: fingerprint}
+		c.stats[fingerprint] = stat
+	}
+
+	stat.Count++
+	if err != nil && err != ErrNotFound {
+		stat.Errors++
+	}
+	stat.Total += duration
+	if duration > stat.Max {
+		stat.Max = duration
+	}
+}
+
+// WithQueryStats customizes a SqlConn to aggregate every executed query into
+// collector, keyed by its normalized fingerprint.
+func WithQueryStats(collector *QueryStatsCollector) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.statsCollector = collector
+	}
+}
+
+func (db *commonSqlConn) observeQuery(q string, duration time.Duration, err error) {
+	if db.statsCollector == nil {
+		return
+	}
+
+	db.statsCollector.observe(db.fingerprint(q), duration, err)
+}
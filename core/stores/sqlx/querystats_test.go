@@ -0,0 +1,65 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryStatsCollector(t *testing.T) {
+	collector := NewQueryStatsCollector()
+	collector.observe("SELECT * FROM t WHERE id = ?", time.Millisecond, nil)
+	collector.observe("SELECT * FROM t WHERE id = ?", time.Millisecond*3, errors.New("any"))
+	collector.observe("UPDATE t SET a = ?", time.Millisecond*2, nil)
+
+	stats := collector.QueryStats()
+	assert.Equal(t, 2, len(stats))
+	assert.Equal(t, "SELECT * FROM t WHERE id = ?", stats[0].Fingerprint)
+	assert.Equal(t, int64(2), stats[0].Count)
+	assert.Equal(t, int64(1), stats[0].Errors)
+	assert.Equal(t, time.Millisecond*4, stats[0].Total)
+	assert.Equal(t, time.Millisecond*3, stats[0].Max)
+	assert.Equal(t, int64(1), stats[1].Count)
+
+	collector.Reset()
+	assert.Empty(t, collector.QueryStats())
+}
+
+func TestQueryStatsNotFoundNotAnError(t *testing.T) {
+	collector := NewQueryStatsCollector()
+	collector.observe("SELECT * FROM t WHERE id = ?", time.Millisecond, ErrNotFound)
+	stats := collector.QueryStats()
+	assert.Equal(t, int64(1), stats[0].Count)
+	assert.Equal(t, int64(0), stats[0].Errors)
+}
+
+func TestWithQueryStats(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`UPDATE users SET name = \? WHERE id = \?`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT name FROM users WHERE id = \?`).WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("first"))
+		mock.ExpectQuery(`SELECT name FROM users WHERE id = \?`).WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("second"))
+
+		collector := NewQueryStatsCollector()
+		conn := NewSqlConnFromDB(db, WithQueryStats(collector))
+
+		_, err := conn.Exec("UPDATE users SET name = ? WHERE id = ?", "first", 1)
+		assert.Nil(t, err)
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "SELECT name FROM users WHERE id = ?", 1))
+		assert.Nil(t, conn.QueryRow(&name, "SELECT name FROM users WHERE id = ?", 2))
+
+		stats := collector.QueryStats()
+		assert.Equal(t, 2, len(stats))
+		assert.Equal(t, "SELECT name FROM users WHERE id = ?", stats[0].Fingerprint)
+		assert.Equal(t, int64(2), stats[0].Count)
+		assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", stats[1].Fingerprint)
+		assert.Equal(t, int64(1), stats[1].Count)
+	})
+}
@@ -0,0 +1,70 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRateLimited is returned when a query is rejected by the connection's
+// rate limiter.
+var ErrRateLimited = errors.New("sql query rate limit exceeded")
+
+// RateLimiter is the limiter consulted before each query, satisfied by
+// golang.org/x/time/rate.Limiter.
+type RateLimiter interface {
+	// Allow reports whether a query may proceed right now.
+	Allow() bool
+	// Wait blocks until a query may proceed, or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter caps the query rate of the connection with the given
+// token-bucket limiter, consulted before execs, queries and transactions.
+// Unlike the breaker, which trips reactively on failures, this throttles
+// proactively to protect a database with a known capacity ceiling. Calls over
+// the limit block until a token frees up or the context deadline expires;
+// combine with WithRateLimitFailFast to reject them immediately instead.
+func WithRateLimiter(limiter RateLimiter) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.rateLimiter = limiter
+	}
+}
+
+// WithRateLimitFailFast makes rate-limited calls fail with ErrRateLimited
+// instead of blocking for a token.
+func WithRateLimitFailFast() SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.rateLimitFailFast = true
+	}
+}
+
+// waitRateLimit acquires a token from the connection's rate limiter, if one
+// is configured. The call's priority overrides the configured fail-fast mode:
+// low-priority queries never wait for a token, high-priority ones always do.
+func (db *commonSqlConn) waitRateLimit(ctx context.Context) error {
+	if db.rateLimiter == nil {
+		return nil
+	}
+
+	failFast := db.rateLimitFailFast
+	switch priorityFromContext(ctx) {
+	case PriorityLow:
+		failFast = true
+	case PriorityHigh:
+		failFast = false
+	}
+
+	if failFast {
+		if !db.rateLimiter.Allow() {
+			return ErrRateLimited
+		}
+		return nil
+	}
+
+	if err := db.rateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrRateLimited, err)
+	}
+
+	return nil
+}
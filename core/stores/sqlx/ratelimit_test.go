@@ -0,0 +1,57 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiterFailFast(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("first"))
+
+		conn := NewSqlConnFromDB(db, WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)),
+			WithRateLimitFailFast())
+
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users where id = ?", 1))
+		// the bucket is drained, the next query is rejected immediately
+		err := conn.QueryRow(&name, "select name from users where id = ?", 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+}
+
+func TestRateLimiterBlocking(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		conn := NewSqlConnFromDB(db, WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)))
+
+		_, err := conn.Exec("update users set name = ? where id = ?", "any", 1)
+		assert.Nil(t, err)
+
+		// with the bucket drained, blocking mode waits until the deadline
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		_, err = conn.ExecCtx(ctx, "update users set name = ? where id = ?", "any", 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+}
+
+func TestRateLimiterTransact(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		conn := NewSqlConnFromDB(db, WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 0)),
+			WithRateLimitFailFast())
+
+		err := conn.Transact(func(session Session) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+}
@@ -0,0 +1,118 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ErrWriteInReadOnlyTx is an error that indicates a write statement ran inside
+// a transaction declared read-only via WithAssertReadOnly.
+var ErrWriteInReadOnlyTx = fmt.Errorf("write statement in read-only transaction")
+
+// checkReadOnlyStmt rejects statements the classifier doesn't recognize as reads.
+func checkReadOnlyStmt(q string) error {
+	switch classifyStatement(q) {
+	case "SELECT", "SHOW", "EXPLAIN", "DESCRIBE", "DESC":
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrWriteInReadOnlyTx, classifyStatement(q))
+	}
+}
+
+// readOnlyGuard wraps a transaction session, classifying every statement and
+// failing writes, to enforce a read-only contract on the fn of TransactCtx.
+type readOnlyGuard struct {
+	trans
+}
+
+// BeforeCommit forwards to the wrapped session, so commit hooks stay available
+// under WithAssertReadOnly.
+func (g readOnlyGuard) BeforeCommit(fn func() error) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.BeforeCommit(fn)
+	}
+}
+
+// AfterCommit forwards to the wrapped session, see BeforeCommit.
+func (g readOnlyGuard) AfterCommit(fn func()) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.AfterCommit(fn)
+	}
+}
+
+func (g readOnlyGuard) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return g.ExecCtx(context.Background(), q, args...)
+}
+
+func (g readOnlyGuard) ExecCtx(ctx context.Context, q string, args ...interface{}) (sql.Result, error) {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return nil, err
+	}
+
+	return g.trans.ExecCtx(ctx, q, args...)
+}
+
+func (g readOnlyGuard) Prepare(q string) (StmtSession, error) {
+	return g.PrepareCtx(context.Background(), q)
+}
+
+func (g readOnlyGuard) PrepareCtx(ctx context.Context, q string) (StmtSession, error) {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return nil, err
+	}
+
+	return g.trans.PrepareCtx(ctx, q)
+}
+
+func (g readOnlyGuard) QueryRow(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowCtx(context.Background(), v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return err
+	}
+
+	return g.trans.QueryRowCtx(ctx, v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowPartial(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowPartialCtx(context.Background(), v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return err
+	}
+
+	return g.trans.QueryRowPartialCtx(ctx, v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRows(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowsCtx(context.Background(), v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowsCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return err
+	}
+
+	return g.trans.QueryRowsCtx(ctx, v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowsPartial(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowsPartialCtx(context.Background(), v, q, args...)
+}
+
+func (g readOnlyGuard) QueryRowsPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	if err := checkReadOnlyStmt(q); err != nil {
+		return err
+	}
+
+	return g.trans.QueryRowsPartialCtx(ctx, v, q, args...)
+}
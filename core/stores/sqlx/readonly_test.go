@@ -0,0 +1,73 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReadOnlyStmt(t *testing.T) {
+	assert.Nil(t, checkReadOnlyStmt("select * from users"))
+	assert.Nil(t, checkReadOnlyStmt("SHOW TABLES"))
+	assert.Nil(t, checkReadOnlyStmt("explain select * from users"))
+	assert.True(t, errors.Is(checkReadOnlyStmt("update users set a = 1"), ErrWriteInReadOnlyTx))
+	assert.True(t, errors.Is(checkReadOnlyStmt("insert into users values (1)"), ErrWriteInReadOnlyTx))
+	assert.True(t, errors.Is(checkReadOnlyStmt("delete from users"), ErrWriteInReadOnlyTx))
+}
+
+func TestTransactAssertReadOnly(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			var name string
+			if err := session.QueryRow(&name, "select name from users where id = 1"); err != nil {
+				return err
+			}
+
+			_, err := session.Exec("update users set name = 'jerry' where id = 1")
+			return err
+		}, WithAssertReadOnly())
+		assert.True(t, errors.Is(err, ErrWriteInReadOnlyTx))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransactAssertReadOnlyPasses(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			var name string
+			return session.QueryRow(&name, "select name from users where id = 1")
+		}, WithAssertReadOnly())
+		assert.Nil(t, err)
+	})
+}
+
+func TestTransactAssertReadOnlyCombinedGuards(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		// stacking more guards on top must not drop the read-only enforcement
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			_, err := session.Exec("update users set age = 1")
+			return err
+		}, WithAssertReadOnly(), WithWarnImplicitCommit(), WithTxStallWarning(time.Minute))
+		assert.True(t, errors.Is(err, ErrWriteInReadOnlyTx))
+	})
+}
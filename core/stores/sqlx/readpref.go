@@ -0,0 +1,49 @@
+package sqlx
+
+import "regexp"
+
+// ReadPreference defines how read queries are routed between master and slave
+// in cluster mode, borrowing the well-understood mongo-style semantics.
+type ReadPreference int
+
+const (
+	// ReadPreferenceDefault follows the cluster flag, reads go to slave only.
+	ReadPreferenceDefault ReadPreference = iota
+	// PrimaryOnly routes all queries to master.
+	PrimaryOnly
+	// PrimaryPreferred routes reads to master, falling back to slave if master is not configured.
+	PrimaryPreferred
+	// SecondaryPreferred routes reads to slave, falling back to master if slave is not configured.
+	SecondaryPreferred
+	// SecondaryOnly routes reads to slave, erroring if slave is not configured
+	// or the lag monitor reports it unhealthy - it never falls back to master.
+	SecondaryOnly
+)
+
+// WithReadPreference customizes a SqlConn with the given read preference.
+func WithReadPreference(pref ReadPreference) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.readPref = pref
+	}
+}
+
+// WithReadPattern replaces the leading-keyword read detection with the given
+// precompiled regex: a statement counts as a read, and is eligible for slave
+// routing, iff the regex matches it. It only classifies reads, the configured
+// ReadPreference still decides where a read goes, non-matching statements go
+// to master regardless, and the pattern is matched against the query after
+// per-query hint comments are appended, so anchored patterns should account
+// for them.
+func WithReadPattern(pattern *regexp.Regexp) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.readPattern = pattern
+	}
+}
+
+func (db *commonSqlConn) matchRead(query string) bool {
+	if db.readPattern != nil {
+		return db.readPattern.MatchString(query)
+	}
+
+	return db.isReadStatement(query)
+}
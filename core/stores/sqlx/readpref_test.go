@@ -0,0 +1,113 @@
+package sqlx
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceRespReadPreference(t *testing.T) {
+	full := map[string]string{
+		"master": "master-dsn",
+		"slave":  "slave-dsn",
+	}
+	masterOnly := map[string]string{
+		"master": "master-dsn",
+	}
+	slaveOnly := map[string]string{
+		"slave": "slave-dsn",
+	}
+
+	tests := []struct {
+		name       string
+		pref       ReadPreference
+		datasource map[string]string
+		query      string
+		expect     string
+		expectErr  bool
+	}{
+		{name: "default read", pref: ReadPreferenceDefault, datasource: full, query: "select * from t", expect: "slave-dsn"},
+		{name: "default write", pref: ReadPreferenceDefault, datasource: full, query: "update t set a = 1", expect: "master-dsn"},
+		{name: "primary only read", pref: PrimaryOnly, datasource: full, query: "select * from t", expect: "master-dsn"},
+		{name: "primary preferred no master", pref: PrimaryPreferred, datasource: slaveOnly, query: "select * from t", expect: "slave-dsn"},
+		{name: "secondary preferred", pref: SecondaryPreferred, datasource: full, query: "select * from t", expect: "slave-dsn"},
+		{name: "secondary preferred no slave", pref: SecondaryPreferred, datasource: masterOnly, query: "select * from t", expect: "master-dsn"},
+		{name: "secondary only no slave", pref: SecondaryOnly, datasource: masterOnly, query: "select * from t", expectErr: true},
+		{name: "write ignores preference", pref: SecondaryOnly, datasource: full, query: "delete from t", expect: "master-dsn"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &commonSqlConn{
+				datasource:  test.datasource,
+				cluster:     true,
+				clusterMode: true,
+				readPref:    test.pref,
+			}
+			ds, err := conn.DataSourceResp(test.query)
+			if test.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expect, ds)
+			}
+		})
+	}
+}
+
+func TestDataSourceRespSingleMode(t *testing.T) {
+	conn := &commonSqlConn{}
+	ds, err := conn.DataSourceResp("select * from t")
+	assert.Nil(t, err)
+	assert.Empty(t, ds)
+}
+
+func TestDataSourceRespReadPattern(t *testing.T) {
+	conn := &commonSqlConn{
+		datasource: map[string]string{
+			"master": "master-dsn",
+			"slave":  "slave-dsn",
+		},
+		cluster:     true,
+		clusterMode: true,
+		readPattern: regexp.MustCompile(`(?i)^(select|show|explain)\b`),
+	}
+
+	tests := []struct {
+		query  string
+		expect string
+	}{
+		{query: "select * from t", expect: "slave-dsn"},
+		{query: "SHOW CREATE TABLE t", expect: "slave-dsn"},
+		{query: "explain select * from t", expect: "slave-dsn"},
+		{query: "update t set a = 1", expect: "master-dsn"},
+		{query: "with cte as (select 1) select * from cte", expect: "master-dsn"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			ds, err := conn.DataSourceResp(test.query)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expect, ds)
+		})
+	}
+}
+
+func TestDataSourceRespSecondaryOnlyLaggingSlave(t *testing.T) {
+	conn := NewSqlConnCluster("mysql", map[string]string{
+		"master": "master-dsn",
+		"slave":  "slave-dsn",
+	}, true, WithMaxReplicaLag(time.Second), WithReadPreference(SecondaryOnly)).(*commonSqlConn)
+
+	// unlike the default routing, explicit SecondaryOnly never reads master
+	conn.lagMonitor.lagging.Set(true)
+	_, err := conn.DataSourceResp("select * from t")
+	assert.NotNil(t, err)
+
+	conn.lagMonitor.lagging.Set(false)
+	ds, err := conn.DataSourceResp("select * from t")
+	assert.Nil(t, err)
+	assert.Equal(t, "slave-dsn", ds)
+}
@@ -0,0 +1,56 @@
+package sqlx
+
+import "sync"
+
+type (
+	// RecordedQuery represents one executed query with its args and the resolved role.
+	RecordedQuery struct {
+		Query string
+		Args  []interface{}
+		Role  string
+	}
+
+	// QueryRecorder captures executed queries in order, a lighter-weight testing aid
+	// than a full mock when running against a real test database.
+	QueryRecorder struct {
+		lock    sync.Mutex
+		queries []RecordedQuery
+	}
+)
+
+// NewQueryRecorder returns a QueryRecorder.
+func NewQueryRecorder() *QueryRecorder {
+	return new(QueryRecorder)
+}
+
+// RecordedQueries returns a copy of the queries recorded so far, in execution order.
+func (r *QueryRecorder) RecordedQueries() []RecordedQuery {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	queries := make([]RecordedQuery, len(r.queries))
+	copy(queries, r.queries)
+	return queries
+}
+
+// Reset drops the recorded queries.
+func (r *QueryRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.queries = nil
+}
+
+func (r *QueryRecorder) record(query RecordedQuery) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.queries = append(r.queries, query)
+}
+
+// WithQueryRecorder customizes a SqlConn to record every executed query into recorder.
+func WithQueryRecorder(recorder *QueryRecorder) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.recorder = recorder
+	}
+}
@@ -0,0 +1,34 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRecorder(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("insert into users").WithArgs("kevin").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("select (.+) from users").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("kevin"))
+
+		recorder := NewQueryRecorder()
+		conn := NewSqlConnFromDB(db, WithQueryRecorder(recorder))
+		_, err := conn.Exec("insert into users (name) values (?)", "kevin")
+		assert.Nil(t, err)
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users where id = ?", 1))
+
+		queries := recorder.RecordedQueries()
+		assert.Equal(t, 2, len(queries))
+		assert.Equal(t, "insert into users (name) values (?)", queries[0].Query)
+		assert.Equal(t, []interface{}{"kevin"}, queries[0].Args)
+		assert.Equal(t, "select name from users where id = ?", queries[1].Query)
+
+		recorder.Reset()
+		assert.Empty(t, recorder.RecordedQueries())
+	})
+}
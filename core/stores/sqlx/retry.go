@@ -0,0 +1,104 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	// retryBaseDelay is the base backoff of the first retry, doubled per attempt.
+	retryBaseDelay = time.Millisecond * 50
+
+	// mysql error 1040, the server has too many connections
+	mysqlErrTooManyConns = 1040
+	// postgres error 53300, too_many_connections
+	pgErrTooManyConns = "53300"
+)
+
+// WithRetry retries statements failing with transient connection exhaustion
+// (MySQL 1040, Postgres 53300) up to attempts times, backing off with jitter
+// between attempts. These errors usually clear within a connection lifetime,
+// so a short retry avoids amplifying traffic spikes into failures.
+func WithRetry(attempts int) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.retries = attempts
+	}
+}
+
+func (db *commonSqlConn) withRetry(ctx context.Context, fn func() error) error {
+	if db.retries <= 0 {
+		return fn()
+	}
+
+	budget := retryBudgetFromContext(ctx)
+	var err error
+	for i := 0; i <= db.retries; i++ {
+		if err = fn(); !isRetryableConnError(err) {
+			return err
+		}
+		if i < db.retries {
+			// a drained request budget stops further retries across all calls
+			if budget != nil && !budget.allow() {
+				return err
+			}
+			time.Sleep(backoffWithJitter(i))
+		}
+	}
+
+	return err
+}
+
+type retryBudgetKey struct{}
+
+// retryBudget caps the total retries of all calls sharing one context.
+type retryBudget struct {
+	remaining int64
+}
+
+// WithRetryBudget caps the total retries of all DB calls made with the
+// returned context at maxRetries, shared across the calls and decremented as
+// retries happen. Once drained, further calls fail without retrying, so a
+// request issuing many queries can't multiply WithRetry into a retry storm.
+func WithRetryBudget(ctx context.Context, maxRetries int) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, &retryBudget{
+		remaining: int64(maxRetries),
+	})
+}
+
+func retryBudgetFromContext(ctx context.Context) *retryBudget {
+	budget, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	return budget
+}
+
+func (b *retryBudget) allow() bool {
+	return atomic.AddInt64(&b.remaining, -1) >= 0
+}
+
+func isRetryableConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrTooManyConns
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pgErrTooManyConns
+	}
+
+	return false
+}
+
+func backoffWithJitter(attempt int) time.Duration {
+	backoff := retryBaseDelay << uint(attempt)
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableConnError(t *testing.T) {
+	assert.False(t, isRetryableConnError(nil))
+	assert.False(t, isRetryableConnError(errors.New("plain")))
+	assert.True(t, isRetryableConnError(&mysql.MySQLError{
+		Number:  mysqlErrTooManyConns,
+		Message: "Too many connections",
+	}))
+	assert.False(t, isRetryableConnError(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, isRetryableConnError(&pq.Error{Code: pgErrTooManyConns}))
+	assert.False(t, isRetryableConnError(&pq.Error{Code: pgErrUnique}))
+}
+
+func TestWithRetryRecovers(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnError(&mysql.MySQLError{
+			Number:  mysqlErrTooManyConns,
+			Message: "Too many connections",
+		})
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("kevin"))
+
+		conn := NewSqlConnFromDB(db, WithRetry(2))
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users limit 1"))
+		assert.Equal(t, "kevin", name)
+	})
+}
+
+func TestWithRetryBudgetStopsRetries(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		// first call retries once, draining the budget; second call can't retry
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery("select (.+) from users").WillReturnError(&mysql.MySQLError{
+				Number:  mysqlErrTooManyConns,
+				Message: "Too many connections",
+			})
+		}
+
+		conn := NewSqlConnFromDB(db, WithRetry(5))
+		ctx := WithRetryBudget(context.Background(), 1)
+		var name string
+		var mysqlErr *mysql.MySQLError
+		err := conn.QueryRowCtx(ctx, &name, "select name from users limit 1")
+		assert.True(t, errors.As(err, &mysqlErr))
+		err = conn.QueryRowCtx(ctx, &name, "select name from users limit 1")
+		assert.True(t, errors.As(err, &mysqlErr))
+	})
+}
+
+func TestWithRetryExhausted(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		for i := 0; i < 2; i++ {
+			mock.ExpectQuery("select (.+) from users").WillReturnError(&mysql.MySQLError{
+				Number:  mysqlErrTooManyConns,
+				Message: "Too many connections",
+			})
+		}
+
+		conn := NewSqlConnFromDB(db, WithRetry(1))
+		var name string
+		err := conn.QueryRow(&name, "select name from users limit 1")
+		var mysqlErr *mysql.MySQLError
+		assert.True(t, errors.As(err, &mysqlErr))
+	})
+}
@@ -0,0 +1,25 @@
+package sqlx
+
+// RoleAcceptable defines an acceptance check that also receives the role the
+// failing call was routed to, "master" or "slave" in cluster mode, empty in
+// single mode or before routing resolved.
+type RoleAcceptable func(err error, role string) bool
+
+// WithRoleAcceptable customizes which errors count against the breaker, like
+// the accept callback of NewMysql, but role-aware: failover logic can accept
+// slave connection errors, since reads fall back to master, without also
+// excusing the same errors on the master. It applies on top of the built-in
+// acceptance rules, it can only accept more, never less.
+func WithRoleAcceptable(accept RoleAcceptable) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.acceptOnRole = accept
+	}
+}
+
+func (db *commonSqlConn) acceptableOnRole(err error, role string) bool {
+	if db.acceptable(err) {
+		return true
+	}
+
+	return db.acceptOnRole != nil && db.acceptOnRole(err, role)
+}
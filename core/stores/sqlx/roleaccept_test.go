@@ -0,0 +1,30 @@
+package sqlx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptableOnRole(t *testing.T) {
+	connErr := errors.New("connection refused")
+	conn := &commonSqlConn{}
+	WithRoleAcceptable(func(err error, role string) bool {
+		// a dead slave is survivable, reads fall back to master
+		return role == "slave"
+	})(conn)
+
+	assert.True(t, conn.acceptableOnRole(connErr, "slave"))
+	assert.False(t, conn.acceptableOnRole(connErr, "master"))
+	assert.False(t, conn.acceptableOnRole(connErr, ""))
+	// built-in rules still apply regardless of role
+	assert.True(t, conn.acceptableOnRole(nil, "master"))
+	assert.True(t, conn.acceptableOnRole(ErrNotFound, "master"))
+}
+
+func TestAcceptableOnRoleWithoutCallback(t *testing.T) {
+	conn := &commonSqlConn{}
+	assert.False(t, conn.acceptableOnRole(errors.New("any"), "slave"))
+	assert.True(t, conn.acceptableOnRole(nil, "slave"))
+}
@@ -0,0 +1,151 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrInvalidSampleSize is an error that indicates the sample size is not positive.
+var ErrInvalidSampleSize = errors.New("sample size must be positive")
+
+// QuerySample queries a uniform sample of at most k rows into v, like
+// QuerySampleCtx.
+func QuerySample(conn SqlConn, v interface{}, k int, query string, args ...interface{}) error {
+	return QuerySampleCtx(context.Background(), conn, v, k, query, args...)
+}
+
+// QuerySampleCtx runs query and fills v, a pointer to a slice, with a uniform
+// reservoir sample of at most k rows. Rows not selected into the sample are
+// skipped without scanning, so profiling the distribution of a huge result set
+// holds k rows in memory instead of all of them. The order of the sampled rows
+// is arbitrary.
+func QuerySampleCtx(ctx context.Context, conn SqlConn, v interface{}, k int,
+	query string, args ...interface{}) error {
+	if k <= 0 {
+		return ErrInvalidSampleSize
+	}
+
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if err := mapping.ValidatePtr(&rv); err != nil {
+		return err
+	}
+	rte := reflect.TypeOf(v).Elem()
+	rve := rv.Elem()
+	if rte.Kind() != reflect.Slice {
+		return ErrUnsupportedValueType
+	}
+	if !rve.CanSet() {
+		return ErrNotSettable
+	}
+
+	ptr := rte.Elem().Kind() == reflect.Ptr
+	base := mapping.Deref(rte.Elem())
+	opts := db.scanOptions(ctx, true)
+
+	var reservoir []reflect.Value
+	var seen int
+	if err := db.queryRows(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			if err := opts.checkContext(); err != nil {
+				return err
+			}
+
+			idx := seen
+			seen++
+			if idx >= k {
+				if idx = rand.Intn(seen); idx >= k {
+					continue
+				}
+			}
+
+			value, err := scanRowValue(rows, base, columns, opts)
+			if err != nil {
+				return err
+			}
+
+			if idx < len(reservoir) {
+				reservoir[idx] = value
+			} else {
+				reservoir = append(reservoir, value)
+			}
+		}
+
+		return rows.Err()
+	}, query, args...); err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(rte, 0, len(reservoir))
+	for _, value := range reservoir {
+		if ptr {
+			slice = reflect.Append(slice, value)
+		} else {
+			slice = reflect.Append(slice, reflect.Indirect(value))
+		}
+	}
+	rve.Set(slice)
+
+	return nil
+}
+
+func scanRowValue(rows *sql.Rows, base reflect.Type, columns []string,
+	opts scanOptions) (reflect.Value, error) {
+	value := reflect.New(base)
+	if scanner, ok := value.Interface().(RowScanner); ok {
+		return value, scanner.ScanRow(rows)
+	}
+
+	switch base.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		values := make([]interface{}, len(columns))
+		values[0] = value.Interface()
+		for i := 1; i < len(values); i++ {
+			var anonymous interface{}
+			values[i] = &anonymous
+		}
+
+		return value, rows.Scan(values...)
+	case reflect.Struct:
+		values, finalize, err := mapStructFieldsIntoSlice(value, columns, opts)
+		if err != nil {
+			return value, err
+		}
+
+		if opts.decimalAsString {
+			wrapDecimalValues(rows, values)
+		}
+		if err := rows.Scan(values...); err != nil {
+			return value, wrapScanError(err, rows, columns, values)
+		}
+
+		finalize()
+		if opts.strictEnum {
+			if err := validateEnums(value); err != nil {
+				return value, err
+			}
+		}
+
+		return value, nil
+	default:
+		return value, ErrUnsupportedValueType
+	}
+}
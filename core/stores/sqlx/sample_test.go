@@ -0,0 +1,65 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func TestQuerySampleFewerRowsThanK(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "first").AddRow(2, "second"))
+
+		var users []struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+		conn := sampleTestConn(db)
+		assert.Nil(t, QuerySample(conn, &users, 10, "SELECT id, name FROM users"))
+		assert.Equal(t, 2, len(users))
+		assert.Equal(t, 1, users[0].ID)
+		assert.Equal(t, "second", users[1].Name)
+	})
+}
+
+func TestQuerySampleKeepsKRows(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id"})
+		present := make(map[int64]bool)
+		for i := int64(1); i <= 100; i++ {
+			rows.AddRow(i)
+			present[i] = true
+		}
+		mock.ExpectQuery("SELECT id FROM users").WillReturnRows(rows)
+
+		var ids []int64
+		conn := sampleTestConn(db)
+		assert.Nil(t, QuerySampleCtx(context.Background(), conn, &ids, 5,
+			"SELECT id FROM users"))
+		assert.Equal(t, 5, len(ids))
+		for _, id := range ids {
+			assert.True(t, present[id])
+		}
+	})
+}
+
+func TestQuerySampleInvalidSize(t *testing.T) {
+	var ids []int64
+	conn := &commonSqlConn{}
+	assert.Equal(t, ErrInvalidSampleSize, QuerySample(conn, &ids, 0, "SELECT id FROM users"))
+}
+
+func sampleTestConn(db *sql.DB) *commonSqlConn {
+	return &commonSqlConn{
+		connProv: func(ds string) (*sql.DB, error) {
+			return db, nil
+		},
+		brk: breaker.NewBreaker(),
+	}
+}
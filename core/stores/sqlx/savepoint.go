@@ -0,0 +1,54 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+)
+
+// A BatchReport summarizes a savepoint batch, with the per-statement errors
+// of the failed ones keyed by their index in the batch.
+type BatchReport struct {
+	Succeeded int
+	Failed    map[int]error
+}
+
+// ExecBatchSavepoints executes stmts on session like ExecBatchSavepointsCtx.
+func ExecBatchSavepoints(session Session, stmts ...BatchStmt) (BatchReport, error) {
+	return ExecBatchSavepointsCtx(context.Background(), session, stmts...)
+}
+
+// ExecBatchSavepointsCtx executes stmts in order on session, wrapping each
+// statement in a savepoint so a failing statement rolls back only its own
+// savepoint and the batch continues, as import jobs want for skippable rows.
+// It returns a report of successes and per-statement failures; the error is
+// only non-nil when the batch itself broke, e.g. a savepoint couldn't be
+// created. session must be a transaction session, savepoints don't exist
+// outside transactions.
+func ExecBatchSavepointsCtx(ctx context.Context, session Session,
+	stmts ...BatchStmt) (BatchReport, error) {
+	report := BatchReport{
+		Failed: make(map[int]error),
+	}
+
+	for i, stmt := range stmts {
+		name := fmt.Sprintf("sqlx_batch_%d", i)
+		if _, err := session.ExecCtx(ctx, "SAVEPOINT "+name); err != nil {
+			return report, err
+		}
+
+		if _, err := session.ExecCtx(ctx, stmt.Query, stmt.Args...); err != nil {
+			report.Failed[i] = err
+			if _, err := session.ExecCtx(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+				return report, err
+			}
+			continue
+		}
+
+		report.Succeeded++
+		if _, err := session.ExecCtx(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
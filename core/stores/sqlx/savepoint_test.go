@@ -0,0 +1,59 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecBatchSavepoints(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		errDup := errors.New("duplicate key")
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sqlx_batch_0").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("RELEASE SAVEPOINT sqlx_batch_0").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sqlx_batch_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into users").WillReturnError(errDup)
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sqlx_batch_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sqlx_batch_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec("RELEASE SAVEPOINT sqlx_batch_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			report, err := ExecBatchSavepoints(session,
+				BatchStmt{Query: "insert into users (name) values ('a')"},
+				BatchStmt{Query: "insert into users (name) values ('a')"},
+				BatchStmt{Query: "insert into users (name) values ('b')"},
+			)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, report.Succeeded)
+			assert.Len(t, report.Failed, 1)
+			assert.Equal(t, errDup, report.Failed[1])
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExecBatchSavepointsBrokenBatch(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sqlx_batch_0").WillReturnError(errors.New("no transaction"))
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		err := conn.Transact(func(session Session) error {
+			_, err := ExecBatchSavepoints(session,
+				BatchStmt{Query: "insert into users (name) values ('a')"})
+			return err
+		})
+		assert.NotNil(t, err)
+	})
+}
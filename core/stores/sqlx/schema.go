@@ -0,0 +1,59 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+)
+
+// WithSchema sets the default schema per role, keyed like the cluster
+// datasource map ("master", "slave"), by running SET search_path on every new
+// connection of that role through the connect hook. Single-node conns use the
+// "master" entry. This lets master and a reporting slave default to different
+// schemas without embedding them in every query.
+func WithSchema(schemas map[string]string) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.schemas = schemas
+	}
+}
+
+// hookFor returns the connect hook for the given datasource, prepending the
+// per-role schema setter to the user hook when one is configured.
+func (db *commonSqlConn) hookFor(ds string) ConnectHook {
+	schema := db.schemaFor(ds)
+	if len(schema) == 0 {
+		return db.connectHook
+	}
+
+	base := db.connectHook
+	stmt := "SET search_path TO " + quoteSchemaIdent(schema)
+	return func(ctx context.Context, conn driver.Conn) error {
+		if err := ExecOnConn(ctx, conn, stmt); err != nil {
+			return err
+		}
+		if base != nil {
+			return base(ctx, conn)
+		}
+		return nil
+	}
+}
+
+func (db *commonSqlConn) schemaFor(ds string) string {
+	if len(db.schemas) == 0 {
+		return ""
+	}
+
+	role := db.roleOf(ds)
+	if len(role) == 0 && !db.clusterMode {
+		// a single-node conn is its own master
+		role = "master"
+	}
+
+	return db.schemas[role]
+}
+
+// quoteSchemaIdent quotes a schema name as a SQL identifier, so it can't break
+// out of the SET statement.
+func quoteSchemaIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
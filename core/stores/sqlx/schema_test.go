@@ -0,0 +1,46 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSchema(t *testing.T) {
+	registerHookTestDriver()
+
+	conn := NewSqlConn("hooktest", "hooktest://with-schema", WithSchema(map[string]string{
+		"master": "reporting",
+	}))
+	c, err := asCommonSqlConn(conn)
+	assert.Nil(t, err)
+	db, err := c.connProv("")
+	assert.Nil(t, err)
+	assert.Nil(t, db.Ping())
+
+	hookExecLock.Lock()
+	defer hookExecLock.Unlock()
+	assert.Contains(t, hookExecs, `SET search_path TO "reporting"`)
+}
+
+func TestSchemaForRoles(t *testing.T) {
+	conn := &commonSqlConn{
+		datasource: map[string]string{
+			"master": "master-dsn",
+			"slave":  "slave-dsn",
+		},
+		clusterMode: true,
+		schemas: map[string]string{
+			"master": "app",
+			"slave":  "reporting",
+		},
+	}
+	assert.Equal(t, "app", conn.schemaFor("master-dsn"))
+	assert.Equal(t, "reporting", conn.schemaFor("slave-dsn"))
+	assert.Empty(t, conn.schemaFor("unknown-dsn"))
+}
+
+func TestQuoteSchemaIdent(t *testing.T) {
+	assert.Equal(t, `"app"`, quoteSchemaIdent("app"))
+	assert.Equal(t, `"a""b"`, quoteSchemaIdent(`a"b`))
+}
@@ -0,0 +1,275 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type (
+	// ScriptOption defines the method to customize script execution.
+	ScriptOption func(options *scriptOptions)
+
+	scriptOptions struct {
+		transactional bool
+	}
+)
+
+// WithScriptTransaction runs all statements of the script in one transaction,
+// rolling back on the first failing statement.
+func WithScriptTransaction() ScriptOption {
+	return func(options *scriptOptions) {
+		options.transactional = true
+	}
+}
+
+// ExecScript executes a multi-statement script on conn, like ExecScriptCtx.
+func ExecScript(conn SqlConn, script string, opts ...ScriptOption) error {
+	return ExecScriptCtx(context.Background(), conn, script, opts...)
+}
+
+// ExecScriptCtx splits script on statement boundaries and executes each statement
+// on the master, for applying migrations and seed data. The splitter respects
+// quoted strings, comments and Postgres dollar-quoting. On error, the returned
+// error reports which statement failed.
+func ExecScriptCtx(ctx context.Context, conn SqlConn, script string, opts ...ScriptOption) error {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	var options scriptOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// scripts contain writes and DDL, always resolve the master
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return err
+	}
+
+	stmts := splitScript(script)
+	if options.transactional {
+		return transactOnConn(ctx, pool, db.beginTx, new(txOptions),
+			func(ctx context.Context, session Session) error {
+				return runScriptStmts(stmts, func(stmt string) error {
+					_, err := session.ExecCtx(ctx, stmt)
+					return err
+				})
+			})
+	}
+
+	return runScriptStmts(stmts, func(stmt string) error {
+		_, err := exec(ctx, pool, stmt)
+		return err
+	})
+}
+
+// A ScriptResult describes the outcome of one script statement, so tooling
+// can report e.g. "statement 3 affected 0 rows".
+type ScriptResult struct {
+	Statement string
+	// Query marks statements that returned rows instead of affecting them.
+	Query        bool
+	RowsAffected int64
+	RowsReturned int64
+}
+
+// ExecScriptResults executes a script like ExecScriptResultsCtx.
+func ExecScriptResults(conn SqlConn, script string, opts ...ScriptOption) ([]ScriptResult, error) {
+	return ExecScriptResultsCtx(context.Background(), conn, script, opts...)
+}
+
+// ExecScriptResultsCtx executes a multi-statement script like ExecScriptCtx,
+// additionally returning a per-statement result: rows affected for writes and
+// row counts for statements returning rows. On error, the results of the
+// statements that already ran are returned alongside it.
+func ExecScriptResultsCtx(ctx context.Context, conn SqlConn, script string,
+	opts ...ScriptOption) ([]ScriptResult, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var options scriptOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// scripts contain writes and DDL, always resolve the master
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return nil, err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return nil, err
+	}
+
+	stmts := splitScript(script)
+	if options.transactional {
+		var results []ScriptResult
+		err := transactOnConn(ctx, pool, db.beginTx, new(txOptions),
+			func(ctx context.Context, session Session) error {
+				tx, ok := underlyingTx(session)
+				if !ok {
+					return ErrUnsupportedConn
+				}
+
+				var err error
+				results, err = runScriptResults(ctx, tx.Tx, stmts)
+				return err
+			})
+		return results, err
+	}
+
+	return runScriptResults(ctx, pool, stmts)
+}
+
+func runScriptResults(ctx context.Context, conn sessionConn, stmts []string) (
+	[]ScriptResult, error) {
+	results := make([]ScriptResult, 0, len(stmts))
+	for i, stmt := range stmts {
+		result := ScriptResult{Statement: stmt}
+		switch classifyStatement(stmt) {
+		case "SELECT", "SHOW", "EXPLAIN", "DESCRIBE", "DESC":
+			result.Query = true
+			if err := query(ctx, conn, func(rows *sql.Rows) error {
+				for rows.Next() {
+					result.RowsReturned++
+				}
+				return rows.Err()
+			}, stmt); err != nil {
+				return results, fmt.Errorf("script statement %d failed: %s: %w", i+1, stmt, err)
+			}
+		default:
+			res, err := exec(ctx, conn, stmt)
+			if err != nil {
+				return results, fmt.Errorf("script statement %d failed: %s: %w", i+1, stmt, err)
+			}
+			if affected, err := res.RowsAffected(); err == nil {
+				result.RowsAffected = affected
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func runScriptStmts(stmts []string, execFn func(stmt string) error) error {
+	for i, stmt := range stmts {
+		if err := execFn(stmt); err != nil {
+			return fmt.Errorf("script statement %d failed: %s: %w", i+1, stmt, err)
+		}
+	}
+
+	return nil
+}
+
+// splitScript splits a script into statements on unquoted semicolons,
+// stripping -- and /* */ comments, and keeping quoted strings and Postgres
+// dollar-quoted bodies intact.
+func splitScript(script string) []string {
+	var stmts []string
+	var sb strings.Builder
+	appendStmt := func() {
+		if stmt := strings.TrimSpace(sb.String()); len(stmt) > 0 {
+			stmts = append(stmts, stmt)
+		}
+		sb.Reset()
+	}
+
+	n := len(script)
+	for i := 0; i < n; {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			sb.WriteByte(c)
+			i++
+			for i < n {
+				sb.WriteByte(script[i])
+				if script[i] == '\\' && c != '`' && i+1 < n {
+					i++
+					sb.WriteByte(script[i])
+					i++
+					continue
+				}
+				if script[i] == c {
+					i++
+					break
+				}
+				i++
+			}
+		case c == '-' && i+1 < n && script[i+1] == '-':
+			for i < n && script[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < n && script[i+1] == '*':
+			i += 2
+			for i+1 < n && (script[i] != '*' || script[i+1] != '/') {
+				i++
+			}
+			i += 2
+			if i > n {
+				i = n
+			}
+		case c == '$':
+			tag, ok := dollarQuoteTag(script[i:])
+			if !ok {
+				sb.WriteByte(c)
+				i++
+				break
+			}
+
+			sb.WriteString(tag)
+			i += len(tag)
+			if end := strings.Index(script[i:], tag); end < 0 {
+				sb.WriteString(script[i:])
+				i = n
+			} else {
+				sb.WriteString(script[i : i+end+len(tag)])
+				i += end + len(tag)
+			}
+		case c == ';':
+			appendStmt()
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	appendStmt()
+
+	return stmts
+}
+
+// dollarQuoteTag reports whether s starts with a dollar-quote tag like $$ or
+// $body$, returning the tag.
+func dollarQuoteTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1], true
+		}
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') &&
+			!('0' <= c && c <= '9') {
+			return "", false
+		}
+	}
+
+	return "", false
+}
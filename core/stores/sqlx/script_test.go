@@ -0,0 +1,110 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitScript(t *testing.T) {
+	stmts := splitScript(`
+-- schema
+create table users (id int, name varchar(64));
+insert into users values (1, 'a;b');
+/* seed; more */
+insert into users values (2, "c;d");
+`)
+	assert.EqualValues(t, []string{
+		"create table users (id int, name varchar(64))",
+		`insert into users values (1, 'a;b')`,
+		`insert into users values (2, "c;d")`,
+	}, stmts)
+}
+
+func TestSplitScriptDollarQuoting(t *testing.T) {
+	stmts := splitScript(`create function f() returns int as $body$
+begin return 1; end;
+$body$ language plpgsql;
+select 1;`)
+	assert.Len(t, stmts, 2)
+	assert.Contains(t, stmts[0], "begin return 1; end;")
+	assert.Equal(t, "select 1", stmts[1])
+}
+
+func TestExecScriptCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("create table users").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		conn := NewSqlConnFromDB(db)
+		assert.Nil(t, ExecScriptCtx(context.Background(), conn,
+			"create table users (id int); insert into users values (1);"))
+	})
+}
+
+func TestExecScriptCtxReportsFailingStmt(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("create table users").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into users").WillReturnError(errors.New("bad insert"))
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		err := ExecScriptCtx(context.Background(), conn,
+			"create table users (id int); insert into users values (1);",
+			WithScriptTransaction())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "statement 2")
+	})
+}
+
+func TestExecScriptUnsupportedConn(t *testing.T) {
+	assert.Equal(t, ErrUnsupportedConn, ExecScript(new(mockedConn), "select 1;"))
+}
+
+func TestExecScriptResults(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b").AddRow("c"))
+		mock.ExpectExec("delete from users").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		conn := NewSqlConnFromDB(db)
+		results, err := ExecScriptResults(conn, `
+			insert into users (name) values ('a'), ('b');
+			select name from users;
+			delete from users where id = -1;
+		`)
+		assert.Nil(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, int64(2), results[0].RowsAffected)
+		assert.False(t, results[0].Query)
+		assert.True(t, results[1].Query)
+		assert.Equal(t, int64(3), results[1].RowsReturned)
+		assert.Equal(t, int64(0), results[2].RowsAffected)
+	})
+}
+
+func TestExecScriptResultsTransactional(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("update users").WillReturnError(errors.New("bad column"))
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		results, err := ExecScriptResults(conn, `
+			insert into users (name) values ('a');
+			update users set nope = 1;
+		`, WithScriptTransaction())
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "statement 2")
+		// the results of already-run statements are preserved
+		assert.Len(t, results, 1)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,37 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// A ShardKeyTagger extracts the shard key of a statement from its context.
+// An empty return leaves the statement untagged.
+type ShardKeyTagger func(ctx context.Context) string
+
+// WithShardKeyTagger prefixes every statement with a routing comment like
+// `/* shard_key=12345 */`, with the key derived from the calling context by
+// tagger, so a sharding proxy in front of the database can route on it.
+// Statement classification ignores leading comments, so read/write split
+// keeps working on tagged statements.
+func WithShardKeyTagger(tagger ShardKeyTagger) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.shardTagger = tagger
+	}
+}
+
+func (db *commonSqlConn) tagQuery(ctx context.Context, q string) string {
+	if db.shardTagger == nil {
+		return q
+	}
+
+	key := db.shardTagger(ctx)
+	if len(key) == 0 {
+		return q
+	}
+
+	// a `*/` inside the key would terminate the comment early, strip it
+	key = strings.ReplaceAll(key, "*/", "")
+	return fmt.Sprintf("/* shard_key=%s */ %s", key, q)
+}
@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type shardKeyCtx struct{}
+
+func TestWithShardKeyTagger(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta("/* shard_key=12345 */ select name from users")).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+		mock.ExpectExec(regexp.QuoteMeta("/* shard_key=12345 */ update users")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		conn := NewSqlConnFromDB(db, WithShardKeyTagger(func(ctx context.Context) string {
+			key, _ := ctx.Value(shardKeyCtx{}).(string)
+			return key
+		}))
+		ctx := context.WithValue(context.Background(), shardKeyCtx{}, "12345")
+
+		var name string
+		assert.Nil(t, conn.QueryRowCtx(ctx, &name, "select name from users where id = 1"))
+		assert.Equal(t, "tom", name)
+		_, err := conn.ExecCtx(ctx, "update users set name = 'jerry' where id = 1")
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithShardKeyTaggerNoKey(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select name from users").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+
+		conn := NewSqlConnFromDB(db, WithShardKeyTagger(func(_ context.Context) string {
+			return ""
+		}))
+
+		var name string
+		assert.Nil(t, conn.QueryRow(&name, "select name from users where id = 1"))
+		assert.Equal(t, "tom", name)
+	})
+}
+
+func TestTaggedQueryClassification(t *testing.T) {
+	// the classifier skips leading comments, so routing still sees a read
+	assert.True(t, matchReadQuery("/* shard_key=12345 */ select * from users"))
+	assert.False(t, matchReadQuery("/* shard_key=12345 */ update users set a = 1"))
+}
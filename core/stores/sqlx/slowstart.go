@@ -0,0 +1,71 @@
+package sqlx
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+// slowStart ramps allowed throughput gradually after the circuit breaker
+// recovers, so full traffic doesn't slam a just-recovered database and re-trip
+// the breaker.
+type slowStart struct {
+	window    time.Duration
+	lock      sync.Mutex
+	tripped   bool
+	rampStart time.Time
+}
+
+// WithRecoverySlowStart ramps traffic back up over the given window after the
+// breaker recovers from an open circuit. During the ramp, the share of allowed
+// calls grows linearly from zero to full, and rejected calls fail with
+// breaker.ErrServiceUnavailable like an open circuit would.
+func WithRecoverySlowStart(window time.Duration) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.slowStart = &slowStart{window: window}
+	}
+}
+
+// allow reports whether a call may proceed, rejecting a shrinking share of the
+// traffic while the recovery ramp is active.
+func (s *slowStart) allow() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.rampStart.IsZero() {
+		return true
+	}
+
+	elapsed := time.Since(s.rampStart)
+	if elapsed >= s.window {
+		s.rampStart = time.Time{}
+		return true
+	}
+
+	return rand.Float64() < float64(elapsed)/float64(s.window)
+}
+
+// ramping reports whether the recovery ramp is active.
+func (s *slowStart) ramping() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return !s.rampStart.IsZero() && time.Since(s.rampStart) < s.window
+}
+
+// observe watches the call outcomes for the open-circuit error and the first
+// success after it, which starts the ramp.
+func (s *slowStart) observe(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err == breaker.ErrServiceUnavailable {
+		s.tripped = true
+		s.rampStart = time.Time{}
+	} else if err == nil && s.tripped {
+		s.tripped = false
+		s.rampStart = time.Now()
+	}
+}
@@ -0,0 +1,47 @@
+package sqlx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func TestSlowStartIdle(t *testing.T) {
+	s := &slowStart{window: time.Second}
+	for i := 0; i < 10; i++ {
+		assert.True(t, s.allow())
+	}
+}
+
+func TestSlowStartRampsAfterRecovery(t *testing.T) {
+	s := &slowStart{window: time.Minute}
+	s.observe(breaker.ErrServiceUnavailable)
+	assert.True(t, s.tripped)
+	// failures other than the open circuit don't start a ramp
+	s.observe(errors.New("query failed"))
+	assert.True(t, s.tripped)
+
+	s.observe(nil)
+	assert.False(t, s.tripped)
+	assert.False(t, s.rampStart.IsZero())
+
+	// right after recovery nearly everything is rejected
+	var allowed int
+	for i := 0; i < 100; i++ {
+		if s.allow() {
+			allowed++
+		}
+	}
+	assert.Less(t, allowed, 10)
+}
+
+func TestSlowStartEndsAfterWindow(t *testing.T) {
+	s := &slowStart{window: time.Millisecond}
+	s.rampStart = time.Now().Add(-time.Second)
+	assert.True(t, s.allow())
+	// the ramp is over, state is reset
+	assert.True(t, s.rampStart.IsZero())
+}
@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// ErrUnsupportedConn is an error that indicates the given SqlConn is not
+// a connection created by this package.
+var ErrUnsupportedConn = errors.New("unsupported SqlConn implementation")
+
+// snapshotRouteQuery is only used to classify the routing, never executed.
+const snapshotRouteQuery = "SELECT 1"
+
+type snapshotKey struct{}
+
+// BeginReadSnapshot opens a read-only repeatable-read transaction on the read
+// datasource, and returns a child context that routes all reads through it,
+// giving multi-query snapshot consistency. Call the returned func to commit and
+// release the transaction, it's rolled back automatically if ctx gets cancelled.
+func BeginReadSnapshot(ctx context.Context, conn SqlConn) (context.Context, func() error, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	datasource, err := db.DataSourceResp(snapshotRouteQuery)
+	if err != nil {
+		logInstanceError(datasource, err)
+		return nil, nil, err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return nil, nil, err
+	}
+
+	tx, err := pool.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	var endErr error
+	done := make(chan struct{})
+	end := func() error {
+		once.Do(func() {
+			close(done)
+			endErr = tx.Commit()
+		})
+		return endErr
+	}
+	threading.GoSafe(func() {
+		select {
+		case <-ctx.Done():
+			once.Do(func() {
+				close(done)
+				endErr = tx.Rollback()
+			})
+		case <-done:
+			// ended explicitly, exit so non-cancellable contexts don't leak
+			// this goroutine
+		}
+	})
+
+	return context.WithValue(ctx, snapshotKey{}, txSession{Tx: tx}), end, nil
+}
+
+func asCommonSqlConn(conn SqlConn) (*commonSqlConn, error) {
+	if db, ok := conn.(*commonSqlConn); ok {
+		return db, nil
+	}
+
+	return nil, ErrUnsupportedConn
+}
+
+func snapshotFromContext(ctx context.Context) (txSession, bool) {
+	session, ok := ctx.Value(snapshotKey{}).(txSession)
+	return session, ok
+}
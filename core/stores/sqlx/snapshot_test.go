@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginReadSnapshot(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select (.+) from users").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).FromCSVString("kevin"))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		ctx, end, err := BeginReadSnapshot(context.Background(), conn)
+		assert.Nil(t, err)
+
+		var name string
+		assert.Nil(t, conn.QueryRowCtx(ctx, &name, "select name from users limit 1"))
+		assert.Equal(t, "kevin", name)
+		assert.Nil(t, end())
+		// end is idempotent
+		assert.Nil(t, end())
+	})
+}
+
+func TestBeginReadSnapshotUnsupportedConn(t *testing.T) {
+	_, _, err := BeginReadSnapshot(context.Background(), new(mockedConn))
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
+
+func TestBeginReadSnapshotNoGoroutineLeak(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		routines := runtime.NumGoroutine()
+		conn := NewSqlConnFromDB(db)
+		// a non-cancellable context must not keep the rollback watcher alive
+		_, end, err := BeginReadSnapshot(context.Background(), conn)
+		assert.Nil(t, err)
+		assert.Nil(t, end())
+
+		for i := 0; i < 100 && runtime.NumGoroutine() > routines; i++ {
+			time.Sleep(time.Millisecond * 10)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), routines)
+	})
+}
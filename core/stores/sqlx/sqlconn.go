@@ -6,9 +6,12 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/breaker"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/timex"
 )
 
 // spanName is used to identify the span name for the SQL execution.
@@ -25,6 +28,10 @@ type (
 	Session interface {
 		Exec(query string, args ...interface{}) (sql.Result, error)
 		ExecCtx(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+		// InTransaction reports whether the session runs inside a transaction,
+		// so helpers relying on tx-only features, e.g. row locking, can validate
+		// their session instead of guessing.
+		InTransaction() bool
 		Prepare(query string) (StmtSession, error)
 		PrepareCtx(ctx context.Context, query string) (StmtSession, error)
 		QueryRow(v interface{}, query string, args ...interface{}) error
@@ -40,11 +47,13 @@ type (
 	// SqlConn only stands for raw connections, so Transact method can be called.
 	SqlConn interface {
 		Session
+		// Ping verifies the master database is reachable.
+		Ping(ctx context.Context) error
 		// RawDB is for other ORM to operate with, use it with caution.
 		// Notice: don't close it.
 		RawDB() (*sql.DB, error)
-		Transact(fn func(Session) error) error
-		TransactCtx(ctx context.Context, fn func(context.Context, Session) error) error
+		Transact(fn func(Session) error, opts ...TransactOption) error
+		TransactCtx(ctx context.Context, fn func(context.Context, Session) error, opts ...TransactOption) error
 	}
 
 	// SqlOption defines the method to customize a sql connection.
@@ -69,14 +78,48 @@ type (
 	// Because CORBA doesn't support PREPARE, so we need to combine the
 	// query arguments into one string and do underlying query without arguments
 	commonSqlConn struct {
-		connProv    connProvider
-		onError     func(string, error)
-		datasource  map[string]string // 在集群模式启用情况下，指定 `master` 和 `slave` 数据库连接信息
-		cluster     bool              // 在集群模式启用情况下，是否启用读写分离
-		clusterMode bool              // 标识当前是单表模式还是集群模式
-		beginTx     beginnable
-		brk         breaker.Breaker
-		accept      func(error) bool
+		connProv            connProvider
+		driverName          string
+		onError             func(string, error)
+		datasource          map[string]string // 在集群模式启用情况下，指定 `master` 和 `slave` 数据库连接信息
+		cluster             bool              // 在集群模式启用情况下，是否启用读写分离
+		clusterMode         bool              // 标识当前是单表模式还是集群模式
+		readPref            ReadPreference
+		readPattern         *regexp.Regexp
+		argValidator        ArgValidator
+		scanCaseInsensitive bool
+		strictColumns       bool
+		normalizer          QueryNormalizer
+		classifyCache       *classifyCache
+		maxLogQueryLen      int
+		policy              *statementPolicy
+		guardUnqualified    bool
+		argsOnError         bool
+		validationQuery     string
+		readTimeout         time.Duration
+		writeTimeout        time.Duration
+		retries             int
+		strictEnum          bool
+		decimalAsString     bool
+		nullHandling        NullHandling
+		connectHook         ConnectHook
+		schemas             map[string]string
+		shardTagger         ShardKeyTagger
+		hinter              QueryHinter
+		writeQueue          *writeReplayer
+		recorder            *QueryRecorder
+		statsCollector      *QueryStatsCollector
+		lagMonitor          *lagMonitor
+		autoExplain         *autoExplain
+		beginTx             beginnable
+		slowStart           *slowStart
+		brk                 breaker.Breaker
+		accept              func(error) bool
+		acceptOnRole        RoleAcceptable
+		rateLimiter         RateLimiter
+		rateLimitFailFast   bool
+		capsLock            sync.Mutex
+		caps                map[string]Capabilities
 	}
 
 	connProvider func(ds string) (*sql.DB, error)
@@ -104,9 +147,7 @@ type (
 // NewSqlConn returns a SqlConn with given driver name and datasource.
 func NewSqlConn(driverName string, datasource string, opts ...SqlOption) SqlConn {
 	conn := &commonSqlConn{
-		connProv: func(ds string) (*sql.DB, error) {
-			return getSqlConn(driverName, datasource)
-		},
+		driverName: driverName,
 		onError: func(ds string, err error) {
 			logInstanceError(datasource, err)
 		},
@@ -116,6 +157,9 @@ func NewSqlConn(driverName string, datasource string, opts ...SqlOption) SqlConn
 		beginTx:     begin,
 		brk:         breaker.NewBreaker(),
 	}
+	conn.connProv = func(ds string) (*sql.DB, error) {
+		return getSqlConnWithValidation(driverName, datasource, conn.validationQuery, conn.hookFor(datasource))
+	}
 	for _, opt := range opts {
 		opt(conn)
 	}
@@ -124,11 +168,15 @@ func NewSqlConn(driverName string, datasource string, opts ...SqlOption) SqlConn
 }
 
 // NewSqlConnCluster returns a SqlConn with given driver name and datasource.
+// The datasource map must contain a "master" entry, a missing one panics here
+// at startup instead of surfacing as a routing error on the first query, and
+// read/write split without a "slave" entry logs a warning since every read
+// silently goes to master.
 func NewSqlConnCluster(driverName string, datasource map[string]string, cluster bool, opts ...SqlOption) SqlConn {
+	validateClusterDatasource(datasource, cluster)
+
 	conn := &commonSqlConn{
-		connProv: func(ds string) (*sql.DB, error) {
-			return getSqlConn(driverName, ds)
-		},
+		driverName: driverName,
 		onError: func(ds string, err error) {
 			logInstanceError(ds, err)
 		},
@@ -138,6 +186,9 @@ func NewSqlConnCluster(driverName string, datasource map[string]string, cluster
 		beginTx:     begin,
 		brk:         breaker.NewBreaker(),
 	}
+	conn.connProv = func(ds string) (*sql.DB, error) {
+		return getSqlConnWithValidation(driverName, ds, conn.validationQuery, conn.hookFor(ds))
+	}
 	for _, opt := range opts {
 		opt(conn)
 	}
@@ -145,7 +196,27 @@ func NewSqlConnCluster(driverName string, datasource map[string]string, cluster
 	return conn
 }
 
-// TODO not used.
+// validateClusterDatasource fails fast on a datasource map that can only
+// produce routing errors at query time.
+func validateClusterDatasource(datasource map[string]string, cluster bool) {
+	if len(datasource["master"]) == 0 {
+		panic(`sqlx: cluster datasource requires a "master" entry`)
+	}
+	if cluster && len(datasource["slave"]) == 0 {
+		logx.Errorf(`sqlx: read/write split is enabled but no "slave" datasource is configured, all reads go to master`)
+	}
+}
+
+// NewSqlConnWithPool returns a SqlConn that uses db as its connection pool,
+// so multiple logical SqlConns can share one pool against the same physical
+// database instead of each holding their own. The caller owns db and is
+// responsible for closing it. Each SqlConn still gets its own breaker, so a
+// misbehaving module doesn't open the circuit for its siblings. Cluster
+// routing doesn't apply, every statement runs on db.
+func NewSqlConnWithPool(db *sql.DB, opts ...SqlOption) SqlConn {
+	return NewSqlConnFromDB(db, opts...)
+}
+
 // NewSqlConnFromDB returns a SqlConn with the given sql.DB.
 // Use it with caution, it's provided for other ORM to interact with.
 func NewSqlConnFromDB(db *sql.DB, opts ...SqlOption) SqlConn {
@@ -167,59 +238,175 @@ func NewSqlConnFromDB(db *sql.DB, opts ...SqlOption) SqlConn {
 }
 
 func (db *commonSqlConn) DataSourceResp(query string) (string, error) {
-	if db.clusterMode {
-		if db.cluster {
-			reg := regexp.MustCompile(`(?U)^.* `).FindAllString(strings.TrimSpace(query), -1)
-			if strings.ToUpper(strings.TrimSpace(reg[0])) == DefaultMatchSql {
-				if _, ok := db.datasource["slave"]; ok {
-					return db.datasource["slave"], nil
-				} else {
-					return "", errors.New("dataSource config slave error")
-				}
-			}
-		}
+	if !db.clusterMode {
+		return "", nil
+	}
+
+	pref := db.readPref
+	if !db.cluster || !db.matchRead(query) {
+		// writes always go to master, and reads too unless read/write split is enabled
+		pref = PrimaryOnly
+	} else if pref == ReadPreferenceDefault {
+		pref = SecondaryOnly
+	}
+
+	master, hasMaster := db.datasource["master"]
+	slave, hasSlave := db.datasource["slave"]
+	// a lagging slave is skipped, reads fall back to master until it catches
+	// up, except under an explicit SecondaryOnly preference
+	slaveUsable := hasSlave && db.slaveHealthy()
 
-		if _, ok := db.datasource["master"]; ok { //only use master
-			return db.datasource["master"], nil
-		} else {
-			return "", errors.New("dataSource config master error")
+	switch pref {
+	case PrimaryPreferred:
+		if hasMaster {
+			return master, nil
 		}
-	} else {
-		return "", nil
+		if slaveUsable {
+			return slave, nil
+		}
+		return "", errors.New("dataSource config master error")
+	case SecondaryPreferred:
+		if slaveUsable {
+			return slave, nil
+		}
+		if hasMaster {
+			return master, nil
+		}
+		return "", errors.New("dataSource config slave error")
+	case SecondaryOnly:
+		if slaveUsable {
+			return slave, nil
+		}
+		// the default routing keeps the lag fallback, an explicit SecondaryOnly
+		// caller can't tolerate master reads
+		if db.readPref == ReadPreferenceDefault && hasSlave && hasMaster {
+			return master, nil
+		}
+		if hasSlave {
+			return "", errors.New("no healthy secondary")
+		}
+		return "", errors.New("dataSource config slave error")
+	default:
+		if hasMaster {
+			return master, nil
+		}
+		return "", errors.New("dataSource config master error")
 	}
 }
 
+func matchReadQuery(query string) bool {
+	return classifyStatement(query) == strings.ToUpper(DefaultMatchSql)
+}
+
+// isReadStatement is the cached counterpart of matchReadQuery.
+func (db *commonSqlConn) isReadStatement(query string) bool {
+	return db.classify(query) == strings.ToUpper(DefaultMatchSql)
+}
+
 func (db *commonSqlConn) Exec(q string, args ...interface{}) (result sql.Result, err error) {
 	return db.ExecCtx(context.Background(), q, args...)
 }
 
+func (db *commonSqlConn) InTransaction() bool {
+	return false
+}
+
 func (db *commonSqlConn) ExecCtx(ctx context.Context, q string, args ...interface{}) (
 	result sql.Result, err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.ExecCtx(ctx, q, args...)
+	}
+
 	ctx, span := startSpan(ctx, "Exec")
 	defer func() {
 		endSpan(span, err)
 	}()
 
-	err = db.brk.DoWithAcceptable(func() error {
-		datasource, err := db.DataSourceResp(q)
-		//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", q, db.cluster, db.datasource, datasource)
-		if err != nil {
-			logInstanceError(datasource, err)
-			return err
-		}
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
 
-		var conn *sql.DB
-		conn, err = db.connProv(datasource)
-		if err != nil {
-			db.onError(datasource, err)
+	if err = db.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	if err = db.checkStatement(q); err != nil {
+		return nil, err
+	}
+	if err = db.checkUnqualifiedWrite(ctx, q); err != nil {
+		return nil, err
+	}
+	if err = db.validateArgs(q, args); err != nil {
+		return nil, err
+	}
+
+	q = db.decorateQuery(ctx, q)
+	db.markFinalQuery(ctx, q)
+	ctx = db.markLogQueryLen(ctx)
+	ctx, cancel := db.applyTimeout(ctx, q)
+	defer cancel()
+
+	startTime := timex.Now()
+	defer func() {
+		db.observeQuery(q, timex.Since(startTime), err)
+	}()
+
+	var role string
+	err = db.withRetry(ctx, func() error {
+		return db.runWithBreaker(ctx, func() error {
+			datasource, err := db.DataSourceResp(q)
+			//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", q, db.cluster, db.datasource, datasource)
+			if err != nil {
+				logInstanceError(datasource, err)
+				return err
+			}
+			role = db.roleOf(datasource)
+
+			var conn *sql.DB
+			conn, err = db.connProv(datasource)
+			if err != nil {
+				db.onError(datasource, err)
+				return err
+			}
+
+			db.recordQuery(datasource, q, args)
+			result, err = exec(db.markArgsOnError(ctx), conn, q, args...)
 			return err
+		}, func(err error) bool {
+			return db.acceptableOnRole(err, role)
+		})
+	})
+	if err != nil && db.maybeQueueWrite(ctx, err, q, args) {
+		return nil, ErrWriteQueued
+	}
+	if err == nil {
+		markExecResult(span, result)
+	}
+
+	return
+}
+
+// runWithBreaker runs fn through the breaker, unless the call opted out via
+// WithoutBreakerCtx.
+func (db *commonSqlConn) runWithBreaker(ctx context.Context, fn func() error,
+	acceptable func(error) bool) error {
+	if breakerDisabled(ctx) {
+		return fn()
+	}
+
+	if db.slowStart != nil {
+		// during the recovery ramp, the scarce capacity goes to user-facing
+		// traffic first, low-priority calls are shed outright
+		if priorityFromContext(ctx) == PriorityLow && db.slowStart.ramping() {
+			return breaker.ErrServiceUnavailable
+		}
+		if !db.slowStart.allow() {
+			return breaker.ErrServiceUnavailable
 		}
 
-		result, err = exec(ctx, conn, q, args...)
+		err := db.brk.DoWithAcceptable(fn, acceptable)
+		db.slowStart.observe(err)
 		return err
-	}, db.acceptable)
+	}
 
-	return
+	return db.brk.DoWithAcceptable(fn, acceptable)
 }
 
 func (db *commonSqlConn) Prepare(query string) (stmt StmtSession, err error) {
@@ -227,11 +414,18 @@ func (db *commonSqlConn) Prepare(query string) (stmt StmtSession, err error) {
 }
 
 func (db *commonSqlConn) PrepareCtx(ctx context.Context, query string) (stmt StmtSession, err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.PrepareCtx(ctx, query)
+	}
+
 	ctx, span := startSpan(ctx, "Prepare")
 	defer func() {
 		endSpan(span, err)
 	}()
 
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(query)))
+
+	var role string
 	err = db.brk.DoWithAcceptable(func() error {
 		datasource, err := db.DataSourceResp(query)
 		//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", query, db.cluster, db.datasource, datasource)
@@ -239,6 +433,7 @@ func (db *commonSqlConn) PrepareCtx(ctx context.Context, query string) (stmt Stm
 			logInstanceError(datasource, err)
 			return err
 		}
+		role = db.roleOf(datasource)
 
 		var conn *sql.DB
 		conn, err = db.connProv(datasource)
@@ -252,12 +447,15 @@ func (db *commonSqlConn) PrepareCtx(ctx context.Context, query string) (stmt Stm
 			return err
 		}
 
+		countStmtPrepared()
 		stmt = statement{
 			query: query,
 			stmt:  st,
 		}
 		return nil
-	}, db.acceptable)
+	}, func(err error) bool {
+		return db.acceptableOnRole(err, role)
+	})
 
 	return
 }
@@ -268,13 +466,19 @@ func (db *commonSqlConn) QueryRow(v interface{}, q string, args ...interface{})
 
 func (db *commonSqlConn) QueryRowCtx(ctx context.Context, v interface{}, q string,
 	args ...interface{}) (err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.QueryRowCtx(ctx, v, q, args...)
+	}
+
 	ctx, span := startSpan(ctx, "QueryRow")
 	defer func() {
 		endSpan(span, err)
 	}()
 
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
+
 	return db.queryRows(ctx, func(rows *sql.Rows) error {
-		return unmarshalRow(v, rows, true)
+		return unmarshalRowWithOptions(v, rows, db.scanOptions(ctx, true))
 	}, q, args...)
 }
 
@@ -284,13 +488,19 @@ func (db *commonSqlConn) QueryRowPartial(v interface{}, q string, args ...interf
 
 func (db *commonSqlConn) QueryRowPartialCtx(ctx context.Context, v interface{},
 	q string, args ...interface{}) (err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.QueryRowPartialCtx(ctx, v, q, args...)
+	}
+
 	ctx, span := startSpan(ctx, "QueryRowPartial")
 	defer func() {
 		endSpan(span, err)
 	}()
 
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
+
 	return db.queryRows(ctx, func(rows *sql.Rows) error {
-		return unmarshalRow(v, rows, false)
+		return unmarshalRowWithOptions(v, rows, db.scanOptions(ctx, false))
 	}, q, args...)
 }
 
@@ -300,13 +510,19 @@ func (db *commonSqlConn) QueryRows(v interface{}, q string, args ...interface{})
 
 func (db *commonSqlConn) QueryRowsCtx(ctx context.Context, v interface{}, q string,
 	args ...interface{}) (err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.QueryRowsCtx(ctx, v, q, args...)
+	}
+
 	ctx, span := startSpan(ctx, "QueryRows")
 	defer func() {
 		endSpan(span, err)
 	}()
 
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
+
 	return db.queryRows(ctx, func(rows *sql.Rows) error {
-		return unmarshalRows(v, rows, true)
+		return unmarshalRowsWithOptions(v, rows, db.scanOptions(ctx, true))
 	}, q, args...)
 }
 
@@ -316,40 +532,83 @@ func (db *commonSqlConn) QueryRowsPartial(v interface{}, q string, args ...inter
 
 func (db *commonSqlConn) QueryRowsPartialCtx(ctx context.Context, v interface{},
 	q string, args ...interface{}) (err error) {
+	if session, ok := TxFromContext(ctx); ok {
+		return session.QueryRowsPartialCtx(ctx, v, q, args...)
+	}
+
 	ctx, span := startSpan(ctx, "QueryRowsPartial")
 	defer func() {
 		endSpan(span, err)
 	}()
 
+	span.SetAttributes(sqlStatementKey.String(db.fingerprint(q)))
+
 	return db.queryRows(ctx, func(rows *sql.Rows) error {
-		return unmarshalRows(v, rows, false)
+		return unmarshalRowsWithOptions(v, rows, db.scanOptions(ctx, false))
 	}, q, args...)
 }
 
+// Ping verifies the master database is reachable. It goes through the breaker
+// like any other call, wrap ctx with WithoutBreakerCtx to probe liveness while
+// the circuit is open.
+func (db *commonSqlConn) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "Ping")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	return db.runWithBreaker(ctx, func() error {
+		datasource, err := db.DataSourceResp("")
+		if err != nil {
+			logInstanceError(datasource, err)
+			return err
+		}
+
+		conn, err := db.connProv(datasource)
+		if err != nil {
+			db.onError(datasource, err)
+			return err
+		}
+
+		return conn.PingContext(ctx)
+	}, db.acceptable)
+}
+
 func (db *commonSqlConn) RawDB() (*sql.DB, error) {
 	// TODO not used.
 	return db.connProv("")
 }
 
-func (db *commonSqlConn) Transact(fn func(Session) error) error {
+func (db *commonSqlConn) Transact(fn func(Session) error, opts ...TransactOption) error {
 	return db.TransactCtx(context.Background(), func(_ context.Context, session Session) error {
 		return fn(session)
-	})
+	}, opts...)
 }
 
-func (db *commonSqlConn) TransactCtx(ctx context.Context, fn func(context.Context, Session) error) (err error) {
+func (db *commonSqlConn) TransactCtx(ctx context.Context, fn func(context.Context, Session) error,
+	opts ...TransactOption) (err error) {
 	ctx, span := startSpan(ctx, "Transact")
 	defer func() {
 		endSpan(span, err)
 	}()
 
-	return db.brk.DoWithAcceptable(func() error {
-		return transact(ctx, db, db.beginTx, fn)
+	if err = db.waitRateLimit(ctx); err != nil {
+		return err
+	}
+
+	return db.runWithBreaker(ctx, func() error {
+		return transact(ctx, db, db.beginTx, fn, opts...)
 	}, db.acceptable)
 }
 
 func (db *commonSqlConn) acceptable(err error) bool {
-	ok := err == nil || err == sql.ErrNoRows || err == sql.ErrTxDone || err == context.Canceled
+	// constraint violations are client errors, they don't count against the breaker
+	var ce *ConstraintError
+	// deadline exceeded is a client-side timeout, a flood of them mustn't
+	// turn a latency blip into a full outage by opening the circuit
+	ok := err == nil || err == sql.ErrNoRows || err == sql.ErrTxDone ||
+		err == context.Canceled || errors.As(err, &ce) ||
+		errors.Is(err, context.DeadlineExceeded)
 	if db.accept == nil {
 		return ok
 	}
@@ -357,33 +616,111 @@ func (db *commonSqlConn) acceptable(err error) bool {
 	return ok || db.accept(err)
 }
 
+func (db *commonSqlConn) recordQuery(datasource, q string, args []interface{}) {
+	if db.recorder == nil {
+		return
+	}
+
+	db.recorder.record(RecordedQuery{
+		Query: q,
+		Args:  args,
+		Role:  db.roleOf(datasource),
+	})
+}
+
+func (db *commonSqlConn) roleOf(datasource string) string {
+	for role, ds := range db.datasource {
+		if ds == datasource {
+			return role
+		}
+	}
+
+	return ""
+}
+
+func (db *commonSqlConn) scanOptions(ctx context.Context, strict bool) scanOptions {
+	return scanOptions{
+		ctx:             ctx,
+		strict:          strict,
+		strictColumns:   db.strictColumns,
+		caseInsensitive: db.scanCaseInsensitive,
+		strictEnum:      db.strictEnum,
+		decimalAsString: db.decimalAsString,
+		nullHandling:    db.nullHandling,
+	}
+}
+
+func (db *commonSqlConn) validateArgs(q string, args []interface{}) error {
+	if db.argValidator == nil {
+		return nil
+	}
+
+	return db.argValidator(q, args)
+}
+
 func (db *commonSqlConn) queryRows(ctx context.Context, scanner func(*sql.Rows) error,
 	q string, args ...interface{}) (err error) {
+	if err := db.waitRateLimit(ctx); err != nil {
+		return err
+	}
+	if err := db.checkStatement(q); err != nil {
+		return err
+	}
+	if err := db.validateArgs(q, args); err != nil {
+		return err
+	}
+
+	q = db.decorateQuery(ctx, q)
+	db.markFinalQuery(ctx, q)
+	ctx = db.markLogQueryLen(ctx)
+	ctx, cancel := db.applyTimeout(ctx, q)
+	defer cancel()
+
+	if session, ok := snapshotFromContext(ctx); ok {
+		// reads in a snapshot context go through its transaction, skipping the
+		// breaker since the transaction is already established.
+		return query(ctx, session.Tx, scanner, q, args...)
+	}
+
 	var qerr error
-	return db.brk.DoWithAcceptable(func() error {
-		datasource, err := db.DataSourceResp(q)
-		//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", q, db.cluster, db.datasource, datasource)
-		if err != nil {
-			logInstanceError(datasource, err)
-			return err
-		}
+	startTime := timex.Now()
+	defer func() {
+		duration := timex.Since(startTime)
+		db.maybeExplain(q, args, duration)
+		db.observeQuery(q, duration, err)
+	}()
 
-		conn, err := db.connProv(datasource)
-		if err != nil {
-			db.onError(datasource, err)
-			return err
-		}
+	var role string
+	return db.withRetry(ctx, func() error {
+		return db.runWithBreaker(ctx, func() error {
+			datasource, err := db.DataSourceResp(q)
+			//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", q, db.cluster, db.datasource, datasource)
+			if err != nil {
+				logInstanceError(datasource, err)
+				return err
+			}
+			datasource = db.resolveConsistentDatasource(ctx, datasource)
+			role = db.roleOf(datasource)
 
-		return query(ctx, conn, func(rows *sql.Rows) error {
-			qerr = scanner(rows)
-			return qerr
-		}, q, args...)
-	}, func(err error) bool {
-		return qerr == err || db.acceptable(err)
+			conn, err := db.connProv(datasource)
+			if err != nil {
+				db.onError(datasource, err)
+				return err
+			}
+
+			db.recordQuery(datasource, q, args)
+			return query(db.markArgsOnError(ctx), conn, func(rows *sql.Rows) error {
+				qerr = scanner(rows)
+				return qerr
+			}, q, args...)
+		}, func(err error) bool {
+			return qerr == err || db.acceptableOnRole(err, role)
+		})
 	})
 }
 
 func (s statement) Close() error {
+	countStmtClosed()
 	return s.stmt.Close()
 }
 
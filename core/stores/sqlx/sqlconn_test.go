@@ -1,7 +1,9 @@
 package sqlx
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"io"
 	"testing"
 
@@ -62,3 +64,56 @@ func buildConn() (mock sqlmock.Sqlmock) {
 
 	return
 }
+
+func TestNewSqlConnWithPool(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// two logical conns sharing one pool
+		users := NewSqlConnWithPool(db)
+		orders := NewSqlConnWithPool(db)
+
+		var name string
+		assert.Nil(t, users.QueryRow(&name, "select name from users where id = 1"))
+		assert.Equal(t, "tom", name)
+		_, err := orders.Exec("update users set name = 'jerry' where id = 1")
+		assert.Nil(t, err)
+
+		pool, err := users.RawDB()
+		assert.Nil(t, err)
+		assert.Equal(t, db, pool)
+		pool, err = orders.RawDB()
+		assert.Nil(t, err)
+		assert.Equal(t, db, pool)
+	})
+}
+
+func TestPing(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("down"))
+
+	conn := NewSqlConnFromDB(db)
+	assert.Nil(t, conn.Ping(context.Background()))
+	// probing stays possible with the breaker bypassed
+	assert.NotNil(t, conn.Ping(WithoutBreakerCtx(context.Background())))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestNewSqlConnClusterValidation(t *testing.T) {
+	assert.PanicsWithValue(t, `sqlx: cluster datasource requires a "master" entry`, func() {
+		NewSqlConnCluster("mysql", map[string]string{"slave": "slave-dsn"}, true)
+	})
+	assert.NotPanics(t, func() {
+		NewSqlConnCluster("mysql", map[string]string{"master": "master-dsn"}, false)
+	})
+	// read/write split without a slave only warns, reads fall back to master
+	assert.NotPanics(t, func() {
+		NewSqlConnCluster("mysql", map[string]string{"master": "master-dsn"}, true)
+	})
+}
@@ -2,7 +2,9 @@ package sqlx
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,18 +21,25 @@ var connManager = syncx.NewResourceManager()
 
 type pingedDB struct {
 	*sql.DB
-	once sync.Once
+	once   sync.Once
+	server string
 }
 
-func getCachedSqlConn(driverName, server string) (*pingedDB, error) {
+func (p *pingedDB) Close() error {
+	notifyCloseListeners(p.server)
+	return p.DB.Close()
+}
+
+func getCachedSqlConn(driverName, server string, hook ConnectHook) (*pingedDB, error) {
 	val, err := connManager.GetResource(server, func() (io.Closer, error) {
-		conn, err := newDBConnection(driverName, server)
+		conn, err := newDBConnection(driverName, server, hook)
 		if err != nil {
 			return nil, err
 		}
 
 		return &pingedDB{
-			DB: conn,
+			DB:     conn,
+			server: server,
 		}, nil
 	})
 	if err != nil {
@@ -41,27 +50,83 @@ func getCachedSqlConn(driverName, server string) (*pingedDB, error) {
 }
 
 func getSqlConn(driverName, server string) (*sql.DB, error) {
-	pdb, err := getCachedSqlConn(driverName, server)
+	return getSqlConnWithValidation(driverName, server, "", nil)
+}
+
+func getSqlConnWithValidation(driverName, server, validationQuery string,
+	hook ConnectHook) (*sql.DB, error) {
+	// fail fast while a recent pool creation failure is backing off, the
+	// retry itself is singleflighted by connManager.
+	if err := acquireConnAttempt(server); err != nil {
+		return nil, err
+	}
+
+	pdb, err := getCachedSqlConn(driverName, server, hook)
 	if err != nil {
+		markConnFailure(server)
 		return nil, err
 	}
 
 	pdb.once.Do(func() {
-		err = pdb.Ping()
+		err = validateConn(pdb.DB, validationQuery)
 	})
 	if err != nil {
+		markConnFailure(server)
 		return nil, err
 	}
 
+	markConnSuccess(server)
 	return pdb.DB, nil
 }
 
-func newDBConnection(driverName, datasource string) (*sql.DB, error) {
+// validateConn checks the connection with the given validation query, or a
+// protocol ping when no query is configured.
+func validateConn(db *sql.DB, validationQuery string) error {
+	if len(validationQuery) == 0 {
+		return db.Ping()
+	}
+
+	rows, err := db.Query(validationQuery)
+	if err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+func newDBConnection(driverName, datasource string, hook ConnectHook) (*sql.DB, error) {
 	conn, err := sql.Open(driverName, datasource)
 	if err != nil {
 		return nil, err
 	}
 
+	if hook != nil {
+		// per-connection init needs a connector, database/sql has no direct hook
+		drv := conn.Driver()
+		if err := conn.Close(); err != nil {
+			return nil, err
+		}
+
+		var connector driver.Connector
+		if dctx, ok := drv.(driver.DriverContext); ok {
+			if connector, err = dctx.OpenConnector(datasource); err != nil {
+				return nil, err
+			}
+		} else {
+			connector = dsnConnector{dsn: datasource, driver: drv}
+		}
+		conn = sql.OpenDB(hookConnector{base: connector, hook: hook})
+	}
+
+	if isInMemoryDatasource(datasource) {
+		// an in-memory database lives in its connection, a pool of connections
+		// would hand out fresh empty databases, so keep a single long-lived one.
+		conn.SetMaxIdleConns(1)
+		conn.SetMaxOpenConns(1)
+		conn.SetConnMaxLifetime(0)
+		return conn, nil
+	}
+
 	// we need to do this until the issue https://github.com/golang/go/issues/9851 get fixed
 	// discussed here https://github.com/go-sql-driver/mysql/issues/257
 	// if the discussed SetMaxIdleTimeout methods added, we'll change this behavior
@@ -72,3 +137,7 @@ func newDBConnection(driverName, datasource string) (*sql.DB, error) {
 
 	return conn, nil
 }
+
+func isInMemoryDatasource(datasource string) bool {
+	return datasource == ":memory:" || strings.Contains(datasource, "mode=memory")
+}
@@ -0,0 +1,34 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInMemoryDatasource(t *testing.T) {
+	assert.True(t, isInMemoryDatasource(":memory:"))
+	assert.True(t, isInMemoryDatasource("file:test.db?cache=shared&mode=memory"))
+	assert.False(t, isInMemoryDatasource("user:pass@tcp(localhost:3306)/db"))
+	assert.False(t, isInMemoryDatasource("test.db"))
+}
+
+func TestValidateConn(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT 1 FROM dual").WillReturnRows(
+			sqlmock.NewRows([]string{"1"}).FromCSVString("1"))
+
+		assert.Nil(t, validateConn(db, "SELECT 1 FROM dual"))
+	})
+}
+
+func TestValidateConnFailure(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT 1 FROM dual").WillReturnError(errors.New("proxy down"))
+
+		assert.NotNil(t, validateConn(db, "SELECT 1 FROM dual"))
+	})
+}
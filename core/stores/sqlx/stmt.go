@@ -25,16 +25,25 @@ func exec(ctx context.Context, conn sessionConn, q string, args ...interface{})
 		return nil, err
 	}
 
+	logStmt := stmt
+	if argsOnErrorOnly(ctx) {
+		logStmt = q
+	}
+	logStmt = prependOpName(ctx, logStmt)
+	logStmt = truncateLogQuery(ctx, logStmt)
+
 	startTime := timex.Now()
 	result, err := conn.ExecContext(ctx, q, args...)
 	duration := timex.Since(startTime)
+	err = wrapDeadlineError(ctx, err, duration)
 	if duration > slowThreshold.Load() {
-		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] exec: slowcall - %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] exec: slowcall - %s", logStmt)
 	} else {
-		logx.WithContext(ctx).WithDuration(duration).Infof("sql exec: %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Infof("sql exec: %s", logStmt)
 	}
 	if err != nil {
 		logSqlError(ctx, stmt, err)
+		err = wrapConstraintError(err)
 	}
 
 	return result, err
@@ -46,16 +55,25 @@ func execStmt(ctx context.Context, conn stmtConn, q string, args ...interface{})
 		return nil, err
 	}
 
+	logStmt := stmt
+	if argsOnErrorOnly(ctx) {
+		logStmt = q
+	}
+	logStmt = prependOpName(ctx, logStmt)
+	logStmt = truncateLogQuery(ctx, logStmt)
+
 	startTime := timex.Now()
 	result, err := conn.ExecContext(ctx, args...)
 	duration := timex.Since(startTime)
+	err = wrapDeadlineError(ctx, err, duration)
 	if duration > slowThreshold.Load() {
-		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] execStmt: slowcall - %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] execStmt: slowcall - %s", logStmt)
 	} else {
-		logx.WithContext(ctx).WithDuration(duration).Infof("sql execStmt: %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Infof("sql execStmt: %s", logStmt)
 	}
 	if err != nil {
 		logSqlError(ctx, stmt, err)
+		err = wrapConstraintError(err)
 	}
 
 	return result, err
@@ -68,13 +86,21 @@ func query(ctx context.Context, conn sessionConn, scanner func(*sql.Rows) error,
 		return err
 	}
 
+	logStmt := stmt
+	if argsOnErrorOnly(ctx) {
+		logStmt = q
+	}
+	logStmt = prependOpName(ctx, logStmt)
+	logStmt = truncateLogQuery(ctx, logStmt)
+
 	startTime := timex.Now()
 	rows, err := conn.QueryContext(ctx, q, args...)
 	duration := timex.Since(startTime)
+	err = wrapDeadlineError(ctx, err, duration)
 	if duration > slowThreshold.Load() {
-		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] query: slowcall - %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] query: slowcall - %s", logStmt)
 	} else {
-		logx.WithContext(ctx).WithDuration(duration).Infof("sql query: %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Infof("sql query: %s", logStmt)
 	}
 	if err != nil {
 		logSqlError(ctx, stmt, err)
@@ -92,13 +118,21 @@ func queryStmt(ctx context.Context, conn stmtConn, scanner func(*sql.Rows) error
 		return err
 	}
 
+	logStmt := stmt
+	if argsOnErrorOnly(ctx) {
+		logStmt = q
+	}
+	logStmt = prependOpName(ctx, logStmt)
+	logStmt = truncateLogQuery(ctx, logStmt)
+
 	startTime := timex.Now()
 	rows, err := conn.QueryContext(ctx, args...)
 	duration := timex.Since(startTime)
+	err = wrapDeadlineError(ctx, err, duration)
 	if duration > slowThreshold.Load() {
-		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] queryStmt: slowcall - %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Slowf("[SQL] queryStmt: slowcall - %s", logStmt)
 	} else {
-		logx.WithContext(ctx).WithDuration(duration).Infof("sql queryStmt: %s", stmt)
+		logx.WithContext(ctx).WithDuration(duration).Infof("sql queryStmt: %s", logStmt)
 	}
 	if err != nil {
 		logSqlError(ctx, stmt, err)
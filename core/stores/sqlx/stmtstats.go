@@ -0,0 +1,55 @@
+package sqlx
+
+import "sync/atomic"
+
+// StmtStats is a snapshot of the prepared statement counters.
+type StmtStats struct {
+	// Prepared counts statements prepared on the database.
+	Prepared int64
+	// Cached counts statements served from a PreparedPool cache.
+	Cached int64
+	// Evicted counts statements dropped from a PreparedPool on bad connections.
+	Evicted int64
+	// Closed counts statements that were closed.
+	Closed int64
+}
+
+var stmtCounters struct {
+	prepared int64
+	cached   int64
+	evicted  int64
+	closed   int64
+}
+
+// GetStmtStats returns a snapshot of the prepared statement counters.
+// Prepared minus Closed is the number of statements currently open, use it in
+// tests to catch statement leaks from missing Close calls.
+func GetStmtStats() StmtStats {
+	return StmtStats{
+		Prepared: atomic.LoadInt64(&stmtCounters.prepared),
+		Cached:   atomic.LoadInt64(&stmtCounters.cached),
+		Evicted:  atomic.LoadInt64(&stmtCounters.evicted),
+		Closed:   atomic.LoadInt64(&stmtCounters.closed),
+	}
+}
+
+// Open returns the number of statements prepared but not yet closed.
+func (s StmtStats) Open() int64 {
+	return s.Prepared - s.Closed
+}
+
+func countStmtPrepared() {
+	atomic.AddInt64(&stmtCounters.prepared, 1)
+}
+
+func countStmtCached() {
+	atomic.AddInt64(&stmtCounters.cached, 1)
+}
+
+func countStmtEvicted() {
+	atomic.AddInt64(&stmtCounters.evicted, 1)
+}
+
+func countStmtClosed() {
+	atomic.AddInt64(&stmtCounters.closed, 1)
+}
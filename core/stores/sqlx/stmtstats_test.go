@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtStats(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectPrepare("select (.+) from users")
+
+		before := GetStmtStats()
+		conn := NewSqlConnFromDB(db)
+		stmt, err := conn.Prepare("select name from users where id = ?")
+		assert.Nil(t, err)
+
+		after := GetStmtStats()
+		assert.Equal(t, before.Prepared+1, after.Prepared)
+		assert.Equal(t, before.Open()+1, after.Open())
+
+		assert.Nil(t, stmt.Close())
+		after = GetStmtStats()
+		assert.Equal(t, before.Closed+1, after.Closed)
+		assert.Equal(t, before.Open(), after.Open())
+	})
+}
+
+func TestStmtStatsCached(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectPrepare("update users")
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		before := GetStmtStats()
+		pool, err := NewPreparedPool(NewSqlConnFromDB(db))
+		assert.Nil(t, err)
+
+		_, err = pool.Exec("update users set active = ?", true)
+		assert.Nil(t, err)
+		_, err = pool.Exec("update users set active = ?", false)
+		assert.Nil(t, err)
+
+		after := GetStmtStats()
+		assert.Equal(t, before.Prepared+1, after.Prepared)
+		assert.Equal(t, before.Cached+1, after.Cached)
+
+		assert.Nil(t, pool.Close())
+		assert.Equal(t, before.Open(), GetStmtStats().Open())
+	})
+}
@@ -0,0 +1,16 @@
+package sqlx
+
+// NewTestConn returns a SqlConn for tests, running the given migrations before
+// returning. In-memory datasources like sqlite3's ":memory:" are pinned to a
+// single shared connection, so schema created by migrations persists across
+// all queries on the returned conn.
+func NewTestConn(driverName, datasource string, migrations ...string) (SqlConn, error) {
+	conn := NewSqlConn(driverName, datasource)
+	for _, migration := range migrations {
+		if _, err := conn.Exec(migration); err != nil {
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,47 @@
+package sqlx
+
+import (
+	"context"
+	"time"
+)
+
+// WithReadTimeout bounds read statements with the given default timeout when
+// the caller's context has no deadline of its own. Reads and writes have very
+// different latency profiles, so they are configured separately, see
+// WithWriteTimeout.
+func WithReadTimeout(timeout time.Duration) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout bounds write statements with the given default timeout when
+// the caller's context has no deadline of its own, see WithReadTimeout.
+func WithWriteTimeout(timeout time.Duration) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.writeTimeout = timeout
+	}
+}
+
+// applyTimeout returns ctx bounded by the read or write default timeout,
+// picked by classifying q. A context that already carries a deadline wins over
+// the defaults. The returned cancel must be called, it's a no-op when no
+// timeout was applied.
+func (db *commonSqlConn) applyTimeout(ctx context.Context, q string) (
+	context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	var timeout time.Duration
+	if db.isReadStatement(q) {
+		timeout = db.readTimeout
+	} else {
+		timeout = db.writeTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,48 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTimeout(t *testing.T) {
+	conn := &commonSqlConn{
+		readTimeout:  time.Second,
+		writeTimeout: time.Minute,
+	}
+
+	ctx, cancel := conn.applyTimeout(context.Background(), "select * from users")
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Millisecond*100)
+
+	ctx, cancel = conn.applyTimeout(context.Background(), "update users set name = 'a'")
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Millisecond*100)
+}
+
+func TestApplyTimeoutKeepsCallerDeadline(t *testing.T) {
+	conn := &commonSqlConn{
+		readTimeout: time.Minute,
+	}
+
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Second)
+	defer parentCancel()
+	ctx, cancel := conn.applyTimeout(parent, "select * from users")
+	defer cancel()
+	assert.Equal(t, parent, ctx)
+}
+
+func TestApplyTimeoutUnconfigured(t *testing.T) {
+	conn := &commonSqlConn{}
+	ctx, cancel := conn.applyTimeout(context.Background(), "select * from users")
+	defer cancel()
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+}
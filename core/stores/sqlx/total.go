@@ -0,0 +1,32 @@
+package sqlx
+
+import "context"
+
+// QueryRowsWithTotal runs countQuery and dataQuery, like QueryRowsWithTotalCtx.
+func QueryRowsWithTotal(conn SqlConn, v interface{}, countQuery, dataQuery string,
+	args ...interface{}) (int64, error) {
+	return QueryRowsWithTotalCtx(context.Background(), conn, v, countQuery, dataQuery, args...)
+}
+
+// QueryRowsWithTotalCtx runs countQuery and dataQuery with the same args for
+// paginated list endpoints, scanning the rows into v and returning the total.
+// Both queries run inside one read snapshot when the conn supports it, so the
+// count and the page are consistent with each other.
+func QueryRowsWithTotalCtx(ctx context.Context, conn SqlConn, v interface{},
+	countQuery, dataQuery string, args ...interface{}) (int64, error) {
+	if snapCtx, end, err := BeginReadSnapshot(ctx, conn); err == nil {
+		ctx = snapCtx
+		defer end()
+	}
+
+	var total int64
+	if err := conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return 0, err
+	}
+
+	if err := conn.QueryRowsCtx(ctx, v, dataQuery, args...); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
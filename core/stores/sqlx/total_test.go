@@ -0,0 +1,43 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsWithTotal(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select count").WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).FromCSVString("10"))
+		mock.ExpectQuery("select (.+) from users").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).FromCSVString("first\nsecond"))
+		mock.ExpectCommit()
+
+		var names []string
+		conn := NewSqlConnFromDB(db)
+		total, err := QueryRowsWithTotal(conn, &names,
+			"select count(*) from users where active = ?",
+			"select name from users where active = ? limit 2", true)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(10), total)
+		assert.EqualValues(t, []string{"first", "second"}, names)
+	})
+}
+
+func TestQueryRowsWithTotalCountError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("select count").WillReturnError(errors.New("count failed"))
+
+		var names []string
+		conn := NewSqlConnFromDB(db)
+		_, err := QueryRowsWithTotal(conn, &names,
+			"select count(*) from users", "select name from users")
+		assert.NotNil(t, err)
+	})
+}
@@ -11,19 +11,70 @@ import (
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
-var sqlAttributeKey = attribute.Key("sql.method")
+var (
+	sqlAttributeKey = attribute.Key("sql.method")
+	// sqlStatementKey carries the normalized query fingerprint, not the raw SQL,
+	// to keep label cardinality bounded.
+	sqlStatementKey = attribute.Key("sql.statement")
+	// sqlOpKey carries the logical operation name set via WithOpName.
+	sqlOpKey = attribute.Key("sql.op")
+	// backendPIDKey carries the server-side connection id, see BackendPIDCtx.
+	backendPIDKey = attribute.Key("sql.backend_pid")
+	// sqlRowsAffectedKey and sqlLastInsertIDKey carry the sql.Result of a
+	// successful exec, see markExecResult.
+	sqlRowsAffectedKey = attribute.Key("sql.rows_affected")
+	sqlLastInsertIDKey = attribute.Key("sql.last_insert_id")
+)
+
+// spanLinksKey carries the span links set via withSpanLinks.
+type spanLinksKey struct{}
+
+// withSpanLinks returns a context whose spans started by startSpan link back to
+// the given span contexts. Fan-out helpers run batched queries on detached
+// contexts, linking keeps those spans connected to the requests they serve.
+func withSpanLinks(ctx context.Context, links []oteltrace.Link) context.Context {
+	if len(links) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, spanLinksKey{}, links)
+}
 
 func startSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
 	tracer := otel.GetTracerProvider().Tracer(trace.TraceName)
-	start, span := tracer.Start(ctx,
-		spanName,
+	opts := []oteltrace.SpanStartOption{
 		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
-	)
+	}
+	if links, ok := ctx.Value(spanLinksKey{}).([]oteltrace.Link); ok {
+		opts = append(opts, oteltrace.WithLinks(links...))
+	}
+
+	start, span := tracer.Start(ctx, spanName, opts...)
 	span.SetAttributes(sqlAttributeKey.String(method))
+	if name := opNameFromContext(ctx); len(name) > 0 {
+		span.SetAttributes(sqlOpKey.String(name))
+	}
 
 	return start, span
 }
 
+// markExecResult records the rows affected and last insert id of a successful
+// exec on the span, to tell a write that matched nothing from one that matched
+// many. Values a driver can't report are omitted, as is a zero insert id since
+// drivers return it for non-insert statements.
+func markExecResult(span oteltrace.Span, result sql.Result) {
+	if result == nil {
+		return
+	}
+
+	if affected, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(sqlRowsAffectedKey.Int64(affected))
+	}
+	if id, err := result.LastInsertId(); err == nil && id > 0 {
+		span.SetAttributes(sqlLastInsertIDKey.Int64(id))
+	}
+}
+
 func endSpan(span oteltrace.Span, err error) {
 	defer span.End()
 
@@ -0,0 +1,68 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExecSpanResultAttributes(t *testing.T) {
+	otp := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(otp)
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`insert into users \(name\) values \(\?\)`).
+			WithArgs("any").WillReturnResult(sqlmock.NewResult(7, 1))
+
+		conn := NewSqlConnFromDB(db)
+		_, err := conn.ExecCtx(context.Background(), "insert into users (name) values (?)", "any")
+		assert.Nil(t, err)
+
+		spans := recorder.Ended()
+		if !assert.Equal(t, 1, len(spans)) {
+			return
+		}
+
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, attr := range spans[0].Attributes() {
+			attrs[attr.Key] = attr.Value
+		}
+		assert.Equal(t, int64(1), attrs[sqlRowsAffectedKey].AsInt64())
+		assert.Equal(t, int64(7), attrs[sqlLastInsertIDKey].AsInt64())
+	})
+}
+
+func TestExecSpanSkipsResultOnError(t *testing.T) {
+	otp := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(otp)
+
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`update users set name = \?`).
+			WithArgs("any").WillReturnError(assert.AnError)
+
+		conn := NewSqlConnFromDB(db)
+		_, err := conn.ExecCtx(context.Background(), "update users set name = ?", "any")
+		assert.NotNil(t, err)
+
+		spans := recorder.Ended()
+		if !assert.Equal(t, 1, len(spans)) {
+			return
+		}
+
+		for _, attr := range spans[0].Attributes() {
+			assert.NotEqual(t, sqlRowsAffectedKey, attr.Key)
+			assert.NotEqual(t, sqlLastInsertIDKey, attr.Key)
+		}
+	})
+}
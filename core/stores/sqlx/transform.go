@@ -0,0 +1,104 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrInvalidTransform is an error that indicates the transform callback
+// doesn't have the func(T) (R, error) shape, or R doesn't match the result slice.
+var ErrInvalidTransform = errors.New("transform must be func(T) (R, error) with R matching the result slice")
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// QueryRowsTransform queries rows and applies transform to each, like
+// QueryRowsTransformCtx.
+func QueryRowsTransform(conn SqlConn, v, transform interface{}, query string,
+	args ...interface{}) error {
+	return QueryRowsTransformCtx(context.Background(), conn, v, transform, query, args...)
+}
+
+// QueryRowsTransformCtx runs query, scans each row into the input type of
+// transform and appends transform's result to v, a pointer to a slice. It
+// keeps per-row post-processing, e.g. decrypting a column or computing a
+// derived field, next to the query instead of in a separate loop, and applies
+// it while streaming so only the transformed values are retained. A transform
+// error aborts the scan and is returned with the row's position.
+func QueryRowsTransformCtx(ctx context.Context, conn SqlConn, v, transform interface{},
+	query string, args ...interface{}) error {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return err
+	}
+
+	tv := reflect.ValueOf(transform)
+	tt := tv.Type()
+	if tt.Kind() != reflect.Func || tt.NumIn() != 1 || tt.NumOut() != 2 ||
+		tt.Out(1) != errorType {
+		return ErrInvalidTransform
+	}
+
+	rv := reflect.ValueOf(v)
+	if err := mapping.ValidatePtr(&rv); err != nil {
+		return err
+	}
+	rte := reflect.TypeOf(v).Elem()
+	rve := rv.Elem()
+	if rte.Kind() != reflect.Slice {
+		return ErrUnsupportedValueType
+	}
+	if !rve.CanSet() {
+		return ErrNotSettable
+	}
+	if rte.Elem() != tt.Out(0) {
+		return ErrInvalidTransform
+	}
+
+	in := tt.In(0)
+	inPtr := in.Kind() == reflect.Ptr
+	base := mapping.Deref(in)
+	opts := db.scanOptions(ctx, true)
+
+	slice := reflect.MakeSlice(rte, 0, 0)
+	if err := db.queryRows(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		var row int
+		for rows.Next() {
+			if err := opts.checkContext(); err != nil {
+				return err
+			}
+
+			value, err := scanRowValue(rows, base, columns, opts)
+			if err != nil {
+				return err
+			}
+
+			if !inPtr {
+				value = reflect.Indirect(value)
+			}
+			outs := tv.Call([]reflect.Value{value})
+			if !outs[1].IsNil() {
+				return fmt.Errorf("transform row %d: %w", row, outs[1].Interface().(error))
+			}
+
+			slice = reflect.Append(slice, outs[0])
+			row++
+		}
+
+		return rows.Err()
+	}, query, args...); err != nil {
+		return err
+	}
+
+	rve.Set(slice)
+	return nil
+}
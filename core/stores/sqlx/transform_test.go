@@ -0,0 +1,59 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRowsTransform(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "first").AddRow(2, "second"))
+
+		type user struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+		var names []string
+		conn := sampleTestConn(db)
+		err := QueryRowsTransform(conn, &names, func(u user) (string, error) {
+			return strings.ToUpper(u.Name), nil
+		}, "SELECT id, name FROM users")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"FIRST", "SECOND"}, names)
+	})
+}
+
+func TestQueryRowsTransformError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT id FROM users").WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+		transformErr := errors.New("bad cipher text")
+		var ids []int64
+		conn := sampleTestConn(db)
+		err := QueryRowsTransform(conn, &ids, func(id int64) (int64, error) {
+			if id > 1 {
+				return 0, transformErr
+			}
+			return id, nil
+		}, "SELECT id FROM users")
+		assert.True(t, errors.Is(err, transformErr))
+		assert.Contains(t, err.Error(), "transform row 1")
+	})
+}
+
+func TestQueryRowsTransformInvalid(t *testing.T) {
+	var ids []int64
+	conn := &commonSqlConn{}
+	assert.Equal(t, ErrInvalidTransform, QueryRowsTransform(conn, &ids,
+		func(id int64) int64 { return id }, "SELECT id FROM users"))
+	assert.Equal(t, ErrInvalidTransform, QueryRowsTransform(conn, &ids,
+		func(id int64) (string, error) { return "", nil }, "SELECT id FROM users"))
+}
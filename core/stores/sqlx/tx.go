@@ -7,7 +7,12 @@ import (
 )
 
 type (
-	beginnable func(*sql.DB) (trans, error)
+	// CommitFunc commits an explicitly managed transaction.
+	CommitFunc func() error
+	// RollbackFunc rolls back an explicitly managed transaction.
+	RollbackFunc func() error
+
+	beginnable func(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (trans, error)
 
 	trans interface {
 		Session
@@ -41,6 +46,10 @@ func (t txSession) ExecCtx(ctx context.Context, q string, args ...interface{}) (
 	return
 }
 
+func (t txSession) InTransaction() bool {
+	return true
+}
+
 func (t txSession) Prepare(q string) (StmtSession, error) {
 	return t.PrepareCtx(context.Background(), q)
 }
@@ -56,6 +65,7 @@ func (t txSession) PrepareCtx(ctx context.Context, q string) (stmtSession StmtSe
 		return nil, err
 	}
 
+	countStmtPrepared()
 	return statement{
 		query: q,
 		stmt:  stmt,
@@ -124,8 +134,72 @@ func (t txSession) QueryRowsPartialCtx(ctx context.Context, v interface{}, q str
 	}, q, args...)
 }
 
-func begin(db *sql.DB) (trans, error) {
-	tx, err := db.Begin()
+// BeginTx begins a transaction on given conn and returns the session together with
+// explicit commit and rollback closures, for callers whose transaction boundary
+// spans multiple methods and doesn't fit the TransactCtx callback style.
+// The caller must call exactly one of commit or rollback, otherwise the underlying
+// connection is held until garbage collection kicks in.
+func BeginTx(ctx context.Context, conn SqlConn, opts ...TransactOption) (
+	Session, CommitFunc, RollbackFunc, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	txOpts := buildTxOptions(opts...)
+	if err := txOpts.validate(db.driverName); err != nil {
+		return nil, nil, nil, err
+	}
+	// implicit commits on DDL are a mysql behavior, no point warning elsewhere
+	txOpts.warnImplicitCommit = txOpts.warnImplicitCommit && db.driverName == mysqlDriverName
+	txOpts.driverName = db.driverName
+
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return nil, nil, nil, err
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return nil, nil, nil, err
+	}
+
+	if txOpts.lazy {
+		tx := newHookedSession(newLazyTx(ctx, pool, db.beginTx, txOpts), txOpts.driverName)
+		session := Session(tx)
+		if txOpts.warnImplicitCommit {
+			session = implicitCommitGuard{trans: tx}
+		}
+		return session, tx.Commit, tx.Rollback, nil
+	}
+
+	rawTx, err := db.beginTx(ctx, pool, txOpts.sqlOpts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tx := newHookedSession(rawTx, txOpts.driverName)
+	for _, stmt := range txOpts.initStatements() {
+		if _, err := tx.ExecCtx(ctx, stmt); err != nil {
+			if e := tx.Rollback(); e != nil {
+				return nil, nil, nil, fmt.Errorf("init statement failed: %s, rollback failed: %w", err, e)
+			}
+			return nil, nil, nil, err
+		}
+	}
+
+	session := Session(tx)
+	if txOpts.warnImplicitCommit {
+		session = implicitCommitGuard{trans: tx}
+	}
+
+	return session, tx.Commit, tx.Rollback, nil
+}
+
+func begin(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (trans, error) {
+	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +210,18 @@ func begin(db *sql.DB) (trans, error) {
 }
 
 func transact(ctx context.Context, db *commonSqlConn, b beginnable,
-	fn func(context.Context, Session) error) (err error) {
+	fn func(context.Context, Session) error, opts ...TransactOption) (err error) {
+	txOpts := buildTxOptions(opts...)
+	if err := txOpts.validate(db.driverName); err != nil {
+		return err
+	}
+	txOpts.warnImplicitCommit = txOpts.warnImplicitCommit && db.driverName == mysqlDriverName
+	txOpts.driverName = db.driverName
+
+	if txOpts.upgrade {
+		return transactUpgrade(ctx, db, b, txOpts, fn)
+	}
+
 	datasource, err := db.DataSourceResp("")
 	//logx.Infof("exec DataSourceResp data %v,%v,%v,%v", "", db.cluster, db.datasource, datasource)
 	if err != nil {
@@ -150,16 +235,21 @@ func transact(ctx context.Context, db *commonSqlConn, b beginnable,
 		return err
 	}
 
-	return transactOnConn(ctx, conn, b, fn)
+	return transactOnConn(ctx, conn, b, txOpts, fn)
 }
 
-func transactOnConn(ctx context.Context, conn *sql.DB, b beginnable,
+func transactOnConn(ctx context.Context, conn *sql.DB, b beginnable, txOpts *txOptions,
 	fn func(context.Context, Session) error) (err error) {
 	var tx trans
-	tx, err = b(conn)
-	if err != nil {
-		return
+	if txOpts.lazy {
+		tx = newLazyTx(ctx, conn, b, txOpts)
+	} else {
+		tx, err = b(ctx, conn, txOpts.sqlOpts)
+		if err != nil {
+			return
+		}
 	}
+	tx = newHookedSession(tx, txOpts.driverName)
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -172,10 +262,43 @@ func transactOnConn(ctx context.Context, conn *sql.DB, b beginnable,
 			if e := tx.Rollback(); e != nil {
 				err = fmt.Errorf("transaction failed: %s, rollback failed: %w", err, e)
 			}
+		} else if txOpts.dryRun {
+			// dry-run never persists, roll back the validated work
+			if e := tx.Rollback(); e != nil {
+				err = fmt.Errorf("dry-run rollback failed: %w", e)
+			}
 		} else {
 			err = tx.Commit()
 		}
 	}()
 
-	return fn(ctx, tx)
+	// a lazy transaction runs the init statements itself, right after its
+	// deferred BEGIN
+	if !txOpts.lazy {
+		for _, stmt := range txOpts.initStatements() {
+			if _, err = tx.ExecCtx(ctx, stmt); err != nil {
+				return
+			}
+		}
+	}
+
+	// the guards chain, each wrapping the previous one, so combined options
+	// all stay in effect
+	guarded := tx
+	if txOpts.assertReadOnly {
+		guarded = readOnlyGuard{trans: guarded}
+	}
+	if txOpts.warnImplicitCommit {
+		guarded = implicitCommitGuard{trans: guarded}
+	}
+	if txOpts.stallWarning > 0 {
+		watchdog := newTxStallWatchdog(ctx, txOpts.stallWarning)
+		defer watchdog.stop()
+		guarded = stallGuard{
+			trans:    guarded,
+			watchdog: watchdog,
+		}
+	}
+
+	return fn(ctx, guarded)
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,7 +16,8 @@ const (
 )
 
 type mockTx struct {
-	status int
+	status  int
+	queries []string
 }
 
 func (mt *mockTx) Commit() error {
@@ -28,9 +30,14 @@ func (mt *mockTx) Exec(q string, args ...interface{}) (sql.Result, error) {
 }
 
 func (mt *mockTx) ExecCtx(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	mt.queries = append(mt.queries, query)
 	return nil, nil
 }
 
+func (mt *mockTx) InTransaction() bool {
+	return true
+}
+
 func (mt *mockTx) Prepare(query string) (StmtSession, error) {
 	return nil, nil
 }
@@ -77,14 +84,46 @@ func (mt *mockTx) Rollback() error {
 }
 
 func beginMock(mock *mockTx) beginnable {
-	return func(*sql.DB) (trans, error) {
+	return func(context.Context, *sql.DB, *sql.TxOptions) (trans, error) {
 		return mock, nil
 	}
 }
 
+func TestBeginTxCommit(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		conn := NewSqlConnFromDB(db)
+		session, commit, _, err := BeginTx(context.Background(), conn)
+		assert.Nil(t, err)
+		_, err = session.ExecCtx(context.Background(), "insert into users (name) values (?)", "foo")
+		assert.Nil(t, err)
+		assert.Nil(t, commit())
+	})
+}
+
+func TestBeginTxRollback(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		conn := NewSqlConnFromDB(db)
+		_, _, rollback, err := BeginTx(context.Background(), conn)
+		assert.Nil(t, err)
+		assert.Nil(t, rollback())
+	})
+}
+
+func TestBeginTxUnsupportedConn(t *testing.T) {
+	_, _, _, err := BeginTx(context.Background(), new(mockedConn))
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
+
 func TestTransactCommit(t *testing.T) {
 	mock := &mockTx{}
-	err := transactOnConn(context.Background(), nil, beginMock(mock),
+	err := transactOnConn(context.Background(), nil, beginMock(mock), new(txOptions),
 		func(context.Context, Session) error {
 			return nil
 		})
@@ -94,10 +133,64 @@ func TestTransactCommit(t *testing.T) {
 
 func TestTransactRollback(t *testing.T) {
 	mock := &mockTx{}
-	err := transactOnConn(context.Background(), nil, beginMock(mock),
+	err := transactOnConn(context.Background(), nil, beginMock(mock), new(txOptions),
 		func(context.Context, Session) error {
 			return errors.New("rollback")
 		})
 	assert.Equal(t, mockRollback, mock.status)
 	assert.NotNil(t, err)
 }
+
+func TestTransactDryRun(t *testing.T) {
+	mock := &mockTx{}
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithDryRun()), func(context.Context, Session) error {
+			return nil
+		})
+	assert.Equal(t, mockRollback, mock.status)
+	assert.Nil(t, err)
+}
+
+func TestTransactDryRunKeepsFnError(t *testing.T) {
+	mock := &mockTx{}
+	fnErr := errors.New("validation failed")
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithDryRun()), func(context.Context, Session) error {
+			return fnErr
+		})
+	assert.Equal(t, mockRollback, mock.status)
+	assert.Equal(t, fnErr, err)
+}
+
+func TestTransactCtxDryRun(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into users").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		var fired bool
+		assert.Nil(t, NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				session.(TxHooks).AfterCommit(func() {
+					fired = true
+				})
+				_, err := session.ExecCtx(ctx, "insert into users (id) values (1)")
+				return err
+			}, WithDryRun()))
+		assert.False(t, fired)
+	})
+}
+
+func TestInTransaction(t *testing.T) {
+	assert.False(t, new(commonSqlConn).InTransaction())
+	assert.True(t, txSession{}.InTransaction())
+	assert.True(t, newHookedSession(txSession{}, mysqlDriverName).InTransaction())
+	assert.True(t, readOnlyGuard{trans: txSession{}}.InTransaction())
+
+	mock := &mockTx{}
+	assert.Nil(t, transactOnConn(context.Background(), nil, beginMock(mock), new(txOptions),
+		func(_ context.Context, session Session) error {
+			assert.True(t, session.InTransaction())
+			return nil
+		}))
+}
@@ -0,0 +1,101 @@
+package sqlx
+
+import "fmt"
+
+type (
+	// TxHooks is implemented by the sessions TransactCtx and BeginTx hand out,
+	// letting callers tie work to the transaction outcome:
+	//
+	//	if hooks, ok := session.(TxHooks); ok {
+	//		hooks.AfterCommit(publishOutboxEvent)
+	//	}
+	//
+	// It enables the transactional-outbox pattern without a second store.
+	TxHooks interface {
+		// BeforeCommit registers fn to run right before the commit. A non-nil
+		// error aborts the commit and rolls the transaction back.
+		BeforeCommit(fn func() error)
+		// AfterCommit registers fn to run after a successful commit. It never
+		// runs on rollback or when the commit fails.
+		AfterCommit(fn func())
+	}
+
+	txHooks struct {
+		beforeCommit []func() error
+		afterCommit  []func()
+	}
+
+	// hookedSession wraps a transaction session, running registered hooks
+	// around its Commit. It also carries the driver dialect for helpers that
+	// need dialect-specific SQL, like advisory locks.
+	hookedSession struct {
+		trans
+		hooks      *txHooks
+		driverName string
+	}
+)
+
+func newHookedSession(tx trans, driverName string) hookedSession {
+	return hookedSession{
+		trans:      tx,
+		hooks:      new(txHooks),
+		driverName: driverName,
+	}
+}
+
+func (s hookedSession) BeforeCommit(fn func() error) {
+	s.hooks.beforeCommit = append(s.hooks.beforeCommit, fn)
+}
+
+func (s hookedSession) AfterCommit(fn func()) {
+	s.hooks.afterCommit = append(s.hooks.afterCommit, fn)
+}
+
+func (s hookedSession) Commit() error {
+	for _, fn := range s.hooks.beforeCommit {
+		if err := fn(); err != nil {
+			if e := s.trans.Rollback(); e != nil {
+				return fmt.Errorf("before commit hook failed: %s, rollback failed: %w", err, e)
+			}
+			return err
+		}
+	}
+
+	if err := s.trans.Commit(); err != nil {
+		return err
+	}
+
+	for _, fn := range s.hooks.afterCommit {
+		fn()
+	}
+
+	return nil
+}
+
+// underlyingTx unwraps session wrappers down to the raw transaction session.
+func underlyingTx(session Session) (txSession, bool) {
+	switch s := session.(type) {
+	case txSession:
+		return s, true
+	case hookedSession:
+		return underlyingTx(s.trans)
+	case readOnlyGuard:
+		return underlyingTx(s.trans)
+	case implicitCommitGuard:
+		return underlyingTx(s.trans)
+	case upgradeSession:
+		return underlyingTx(s.trans)
+	case stallGuard:
+		return underlyingTx(s.trans)
+	case *lazyTx:
+		s.lock.Lock()
+		tx := s.tx
+		s.lock.Unlock()
+		if tx == nil {
+			return txSession{}, false
+		}
+		return underlyingTx(tx)
+	default:
+		return txSession{}, false
+	}
+}
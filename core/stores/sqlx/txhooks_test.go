@@ -0,0 +1,91 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxHooksAfterCommit(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into outbox").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		var fired bool
+		assert.Nil(t, NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				hooks, ok := session.(TxHooks)
+				assert.True(t, ok)
+				hooks.AfterCommit(func() {
+					fired = true
+				})
+				_, err := session.ExecCtx(ctx, "insert into outbox (id) values (1)")
+				return err
+			}))
+		assert.True(t, fired)
+	})
+}
+
+func TestTxHooksNotRunOnRollback(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		var fired bool
+		assert.NotNil(t, NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				session.(TxHooks).AfterCommit(func() {
+					fired = true
+				})
+				return errors.New("business error")
+			}))
+		assert.False(t, fired)
+	})
+}
+
+func TestTxHooksBeforeCommitAborts(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		abort := errors.New("abort commit")
+		var fired bool
+		err := NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				hooks := session.(TxHooks)
+				hooks.BeforeCommit(func() error {
+					return abort
+				})
+				hooks.AfterCommit(func() {
+					fired = true
+				})
+				return nil
+			})
+		assert.Equal(t, abort, err)
+		assert.False(t, fired)
+	})
+}
+
+func TestTxHooksReadOnlyGuard(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var fired bool
+		assert.Nil(t, NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				hooks, ok := session.(TxHooks)
+				assert.True(t, ok)
+				hooks.AfterCommit(func() {
+					fired = true
+				})
+				return nil
+			}, WithAssertReadOnly()))
+		assert.True(t, fired)
+	})
+}
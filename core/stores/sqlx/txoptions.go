@@ -0,0 +1,129 @@
+package sqlx
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	postgresDriverName     = "postgres"
+	setConstraintsDeferred = "SET CONSTRAINTS ALL DEFERRED"
+)
+
+// ErrDeferredConstraintsUnsupported is an error that indicates deferred constraints
+// are requested on a driver other than postgres.
+var ErrDeferredConstraintsUnsupported = errors.New("deferred constraints are only supported on postgres")
+
+type (
+	// TransactOption defines the method to customize a transaction.
+	TransactOption func(*txOptions)
+
+	txOptions struct {
+		deferredConstraints bool
+		upgrade             bool
+		lazy                bool
+		assertReadOnly      bool
+		dryRun              bool
+		warnImplicitCommit  bool
+		stallWarning        time.Duration
+		driverName          string
+		sqlOpts             *sql.TxOptions
+		extraInit           []string
+	}
+)
+
+// WithDeferredConstraints makes the transaction run with all deferrable constraints
+// deferred until commit, only supported on postgres.
+func WithDeferredConstraints() TransactOption {
+	return func(o *txOptions) {
+		o.deferredConstraints = true
+	}
+}
+
+// WithAssertReadOnly enforces a read-only contract on the transaction: every
+// statement is classified, and anything the classifier doesn't recognize as a
+// read fails with ErrWriteInReadOnlyTx, rolling the transaction back.
+func WithAssertReadOnly() TransactOption {
+	return func(o *txOptions) {
+		o.assertReadOnly = true
+	}
+}
+
+// WithTxOptions begins the transaction with the given standard sql.TxOptions,
+// setting the isolation level and read-only flag. For driver-specific modes
+// the standard bag can't express, see WithTxInitStatement.
+func WithTxOptions(opts *sql.TxOptions) TransactOption {
+	return func(o *txOptions) {
+		o.sqlOpts = opts
+	}
+}
+
+// WithTxInitStatement runs stmt right after BEGIN, before any statement of fn,
+// as an escape hatch for driver-specific transaction modes, e.g.
+// `SET TRANSACTION READ ONLY DEFERRABLE` on postgres. The statement is passed
+// to the driver verbatim, validity is on the caller.
+func WithTxInitStatement(stmt string) TransactOption {
+	return func(o *txOptions) {
+		o.extraInit = append(o.extraInit, stmt)
+	}
+}
+
+// WithWarnImplicitCommit logs an error when a DDL statement runs inside the
+// transaction on MySQL, where DDL commits the transaction implicitly and
+// silently breaks the assumption that everything rolls back together. The
+// statement still executes, the log is there to catch the correctness bug.
+// On other drivers the option does nothing.
+func WithWarnImplicitCommit() TransactOption {
+	return func(o *txOptions) {
+		o.warnImplicitCommit = true
+	}
+}
+
+// WithDryRun rolls the transaction back at the end even when fn succeeds, so
+// complex writes can be validated, constraint violations included, without
+// persisting anything. fn's error is returned unchanged, and after-commit
+// hooks never fire since nothing commits.
+func WithDryRun() TransactOption {
+	return func(o *txOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithTransactUpgrade begins the transaction read-only on a slave and upgrades
+// it to master on the first write, for read-heavy transactions that rarely
+// write. See transactUpgrade for the re-execution semantics of the upgrade.
+func WithTransactUpgrade() TransactOption {
+	return func(o *txOptions) {
+		o.upgrade = true
+	}
+}
+
+func buildTxOptions(opts ...TransactOption) *txOptions {
+	var o txOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &o
+}
+
+func (o *txOptions) initStatements() []string {
+	var stmts []string
+	if o.deferredConstraints {
+		stmts = append(stmts, setConstraintsDeferred)
+	}
+
+	return append(stmts, o.extraInit...)
+}
+
+func (o *txOptions) validate(driverName string) error {
+	if o.deferredConstraints && driverName != postgresDriverName {
+		return ErrDeferredConstraintsUnsupported
+	}
+	if o.lazy && o.upgrade {
+		return ErrLazyUpgradeUnsupported
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactDeferredConstraints(t *testing.T) {
+	mock := &mockTx{}
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithDeferredConstraints()),
+		func(context.Context, Session) error {
+			return nil
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{setConstraintsDeferred}, mock.queries)
+	assert.Equal(t, mockCommit, mock.status)
+}
+
+func TestTransactInitStatement(t *testing.T) {
+	mock := &mockTx{}
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithTxInitStatement("SET TRANSACTION READ ONLY DEFERRABLE")),
+		func(context.Context, Session) error {
+			return nil
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SET TRANSACTION READ ONLY DEFERRABLE"}, mock.queries)
+	assert.Equal(t, mockCommit, mock.status)
+}
+
+func TestTransactTxOptions(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		assert.Nil(t, NewSqlConnFromDB(db).TransactCtx(context.Background(),
+			func(ctx context.Context, session Session) error {
+				return nil
+			}, WithTxOptions(&sql.TxOptions{ReadOnly: true})))
+	})
+}
+
+func TestTxOptionsValidate(t *testing.T) {
+	opts := buildTxOptions(WithDeferredConstraints())
+	assert.Nil(t, opts.validate(postgresDriverName))
+	assert.Equal(t, ErrDeferredConstraintsUnsupported, opts.validate(mysqlDriverName))
+	assert.Nil(t, buildTxOptions().validate(mysqlDriverName))
+}
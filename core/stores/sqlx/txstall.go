@@ -0,0 +1,159 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// WithTxStallWarning warns when the transaction stays open longer than
+// threshold without issuing a statement, which usually means external IO, an
+// HTTP call or the like, runs inside the transaction while it holds a
+// connection and row locks. The warning carries the op-name and the stack of
+// the transaction start. Every statement resets the timer, so only genuine
+// stalls between statements are reported.
+func WithTxStallWarning(threshold time.Duration) TransactOption {
+	return func(o *txOptions) {
+		o.stallWarning = threshold
+	}
+}
+
+// txStallWatchdog tracks the gap between statements of a transaction, warning
+// once per stall when it exceeds the threshold.
+type txStallWatchdog struct {
+	ctx       context.Context
+	threshold time.Duration
+	opName    string
+	stack     []byte
+	stalls    int32
+	lock      sync.Mutex
+	timer     *time.Timer
+	stopped   bool
+}
+
+func newTxStallWatchdog(ctx context.Context, threshold time.Duration) *txStallWatchdog {
+	w := &txStallWatchdog{
+		ctx:       ctx,
+		threshold: threshold,
+		opName:    opNameFromContext(ctx),
+		stack:     debug.Stack(),
+	}
+	w.timer = time.AfterFunc(threshold, w.warn)
+
+	return w
+}
+
+// touch restarts the stall timer, called on every statement.
+func (w *txStallWatchdog) touch() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.stopped {
+		w.timer.Reset(w.threshold)
+	}
+}
+
+// stop disarms the watchdog, called when the transaction ends.
+func (w *txStallWatchdog) stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.stopped = true
+	w.timer.Stop()
+}
+
+func (w *txStallWatchdog) warn() {
+	atomic.AddInt32(&w.stalls, 1)
+	op := w.opName
+	if len(op) == 0 {
+		op = "unknown"
+	}
+	logx.WithContext(w.ctx).Errorf("transaction open for over %v without a statement, "+
+		"op: %s, likely external IO inside the transaction, begun at:\n%s",
+		w.threshold, op, w.stack)
+}
+
+// stallGuard wraps a transaction session, feeding the watchdog on every
+// statement so gaps between statements are measurable.
+type stallGuard struct {
+	trans
+	watchdog *txStallWatchdog
+}
+
+// BeforeCommit forwards to the wrapped session, so commit hooks stay available
+// under WithTxStallWarning.
+func (g stallGuard) BeforeCommit(fn func() error) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.BeforeCommit(fn)
+	}
+}
+
+// AfterCommit forwards to the wrapped session, see BeforeCommit.
+func (g stallGuard) AfterCommit(fn func()) {
+	if hooks, ok := g.trans.(TxHooks); ok {
+		hooks.AfterCommit(fn)
+	}
+}
+
+func (g stallGuard) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return g.ExecCtx(context.Background(), q, args...)
+}
+
+func (g stallGuard) ExecCtx(ctx context.Context, q string, args ...interface{}) (sql.Result, error) {
+	g.watchdog.touch()
+	return g.trans.ExecCtx(ctx, q, args...)
+}
+
+func (g stallGuard) Prepare(q string) (StmtSession, error) {
+	return g.PrepareCtx(context.Background(), q)
+}
+
+func (g stallGuard) PrepareCtx(ctx context.Context, q string) (StmtSession, error) {
+	g.watchdog.touch()
+	return g.trans.PrepareCtx(ctx, q)
+}
+
+func (g stallGuard) QueryRow(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowCtx(context.Background(), v, q, args...)
+}
+
+func (g stallGuard) QueryRowCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	g.watchdog.touch()
+	return g.trans.QueryRowCtx(ctx, v, q, args...)
+}
+
+func (g stallGuard) QueryRowPartial(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowPartialCtx(context.Background(), v, q, args...)
+}
+
+func (g stallGuard) QueryRowPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	g.watchdog.touch()
+	return g.trans.QueryRowPartialCtx(ctx, v, q, args...)
+}
+
+func (g stallGuard) QueryRows(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowsCtx(context.Background(), v, q, args...)
+}
+
+func (g stallGuard) QueryRowsCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	g.watchdog.touch()
+	return g.trans.QueryRowsCtx(ctx, v, q, args...)
+}
+
+func (g stallGuard) QueryRowsPartial(v interface{}, q string, args ...interface{}) error {
+	return g.QueryRowsPartialCtx(context.Background(), v, q, args...)
+}
+
+func (g stallGuard) QueryRowsPartialCtx(ctx context.Context, v interface{}, q string,
+	args ...interface{}) error {
+	g.watchdog.touch()
+	return g.trans.QueryRowsPartialCtx(ctx, v, q, args...)
+}
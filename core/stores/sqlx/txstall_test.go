@@ -0,0 +1,44 @@
+package sqlx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxStallWatchdogWarnsOnStall(t *testing.T) {
+	watchdog := newTxStallWatchdog(context.Background(), time.Millisecond*10)
+	defer watchdog.stop()
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&watchdog.stalls) > 0
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestTxStallWatchdogTouchResets(t *testing.T) {
+	watchdog := newTxStallWatchdog(context.Background(), time.Millisecond*50)
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Millisecond * 10)
+		watchdog.touch()
+	}
+	watchdog.stop()
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&watchdog.stalls))
+}
+
+func TestTxStallWarningPassesStatementsThrough(t *testing.T) {
+	mock := &mockTx{}
+	err := transactOnConn(context.Background(), nil, beginMock(mock),
+		buildTxOptions(WithTxStallWarning(time.Minute)),
+		func(ctx context.Context, session Session) error {
+			_, err := session.ExecCtx(ctx, "UPDATE users SET age = ?", 1)
+			return err
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, mockCommit, mock.status)
+	assert.Equal(t, []string{"UPDATE users SET age = ?"}, mock.queries)
+}
@@ -0,0 +1,92 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// errTxUpgrade is the internal signal that an optimistic read-only transaction
+// issued a write and has to restart on master.
+var errTxUpgrade = errors.New("transaction write on slave, upgrading to master")
+
+// upgradeSession wraps a read-only transaction running on a slave, turning the
+// first write into an upgrade-to-master signal instead of executing it.
+type upgradeSession struct {
+	trans
+}
+
+func (s upgradeSession) Exec(q string, args ...interface{}) (sql.Result, error) {
+	return s.ExecCtx(context.Background(), q, args...)
+}
+
+func (s upgradeSession) ExecCtx(_ context.Context, _ string, _ ...interface{}) (sql.Result, error) {
+	return nil, errTxUpgrade
+}
+
+// transactUpgrade runs fn optimistically in a read-only transaction on a slave,
+// and restarts it on master when fn issues its first write. On restart fn is
+// re-executed from the beginning, so every statement before the first write
+// runs twice and must be a side-effect-free read. fn must propagate the errors
+// of session.Exec, swallowing them suppresses the upgrade.
+func transactUpgrade(ctx context.Context, db *commonSqlConn, b beginnable, txOpts *txOptions,
+	fn func(context.Context, Session) error) error {
+	slave, hasSlave := db.datasource["slave"]
+	if db.clusterMode && hasSlave && db.slaveHealthy() {
+		conn, err := db.connProv(slave)
+		if err != nil {
+			db.onError(slave, err)
+		} else if err = tryReadOnlyTransact(ctx, conn, txOpts, fn); !errors.Is(err, errTxUpgrade) {
+			return err
+		}
+	}
+
+	master, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(master, err)
+		return err
+	}
+
+	conn, err := db.connProv(master)
+	if err != nil {
+		db.onError(master, err)
+		return err
+	}
+
+	return transactOnConn(ctx, conn, b, txOpts, fn)
+}
+
+func tryReadOnlyTransact(ctx context.Context, conn *sql.DB, txOpts *txOptions,
+	fn func(context.Context, Session) error) (err error) {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if e := tx.Rollback(); e != nil {
+				err = fmt.Errorf("recover from %#v, rollback failed: %w", p, e)
+			} else {
+				err = fmt.Errorf("recover from %#v", p)
+			}
+		} else if err != nil {
+			// rollback failures on the upgrade path are ignored,
+			// the transaction restarts on master anyway
+			if e := tx.Rollback(); e != nil && !errors.Is(err, errTxUpgrade) {
+				err = fmt.Errorf("transaction failed: %s, rollback failed: %w", err, e)
+			}
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	for _, stmt := range txOpts.initStatements() {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return
+		}
+	}
+
+	return fn(ctx, upgradeSession{trans: txSession{Tx: tx}})
+}
@@ -0,0 +1,120 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func buildUpgradeConn(t *testing.T) (*commonSqlConn, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	masterDB, masterMock, err := sqlmock.New()
+	assert.Nil(t, err)
+	slaveDB, slaveMock, err := sqlmock.New()
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		masterDB.Close()
+		slaveDB.Close()
+	})
+
+	conn := &commonSqlConn{
+		connProv: func(ds string) (*sql.DB, error) {
+			if ds == "slave-dsn" {
+				return slaveDB, nil
+			}
+			return masterDB, nil
+		},
+		onError: func(_ string, _ error) {},
+		datasource: map[string]string{
+			"master": "master-dsn",
+			"slave":  "slave-dsn",
+		},
+		cluster:     true,
+		clusterMode: true,
+		beginTx:     begin,
+	}
+
+	return conn, masterMock, slaveMock
+}
+
+func TestTransactUpgradeReadOnly(t *testing.T) {
+	conn, _, slaveMock := buildUpgradeConn(t)
+	slaveMock.ExpectBegin()
+	slaveMock.ExpectQuery("select (.+) from users").WillReturnRows(
+		sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+	slaveMock.ExpectCommit()
+
+	var name string
+	err := transactUpgrade(context.Background(), conn, begin, &txOptions{},
+		func(ctx context.Context, session Session) error {
+			return session.QueryRowCtx(ctx, &name, "select name from users where id = 1")
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, "tom", name)
+	assert.Nil(t, slaveMock.ExpectationsWereMet())
+}
+
+func TestTransactUpgradeOnWrite(t *testing.T) {
+	conn, masterMock, slaveMock := buildUpgradeConn(t)
+	slaveMock.ExpectBegin()
+	slaveMock.ExpectQuery("select (.+) from users").WillReturnRows(
+		sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+	slaveMock.ExpectRollback()
+	masterMock.ExpectBegin()
+	masterMock.ExpectQuery("select (.+) from users").WillReturnRows(
+		sqlmock.NewRows([]string{"name"}).AddRow("tom"))
+	masterMock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+	masterMock.ExpectCommit()
+
+	var runs int
+	err := transactUpgrade(context.Background(), conn, begin, &txOptions{},
+		func(ctx context.Context, session Session) error {
+			runs++
+			var name string
+			if err := session.QueryRowCtx(ctx, &name, "select name from users where id = 1"); err != nil {
+				return err
+			}
+			_, err := session.ExecCtx(ctx, "update users set name = 'jerry' where id = 1")
+			return err
+		})
+	assert.Nil(t, err)
+	// the read before the first write runs on both slave and master
+	assert.Equal(t, 2, runs)
+	assert.Nil(t, slaveMock.ExpectationsWereMet())
+	assert.Nil(t, masterMock.ExpectationsWereMet())
+}
+
+func TestTransactUpgradeWriteError(t *testing.T) {
+	conn, masterMock, slaveMock := buildUpgradeConn(t)
+	slaveMock.ExpectBegin()
+	slaveMock.ExpectRollback()
+	masterMock.ExpectBegin()
+	masterMock.ExpectExec("update users").WillReturnError(errors.New("update failed"))
+	masterMock.ExpectRollback()
+
+	err := transactUpgrade(context.Background(), conn, begin, &txOptions{},
+		func(ctx context.Context, session Session) error {
+			_, err := session.ExecCtx(ctx, "update users set name = 'jerry' where id = 1")
+			return err
+		})
+	assert.NotNil(t, err)
+	assert.Nil(t, slaveMock.ExpectationsWereMet())
+	assert.Nil(t, masterMock.ExpectationsWereMet())
+}
+
+func TestTransactCtxWithUpgradeOption(t *testing.T) {
+	conn, _, slaveMock := buildUpgradeConn(t)
+	conn.brk = breaker.NewBreaker()
+	slaveMock.ExpectBegin()
+	slaveMock.ExpectCommit()
+
+	err := conn.TransactCtx(context.Background(), func(_ context.Context, _ Session) error {
+		return nil
+	}, WithTransactUpgrade())
+	assert.Nil(t, err)
+	assert.Nil(t, slaveMock.ExpectationsWereMet())
+}
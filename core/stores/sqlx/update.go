@@ -0,0 +1,145 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/mapping"
+)
+
+// ErrNoColumnsToUpdate is an error that indicates no db tagged fields to build the set clause with.
+var ErrNoColumnsToUpdate = errors.New("no columns to update")
+
+type (
+	// UpdateOption defines the method to customize an UpdateCtx call.
+	UpdateOption func(*updateOptions)
+
+	updateOptions struct {
+		nilPointers bool
+		createdCol  string
+		updatedCol  string
+	}
+)
+
+// WithNullableFields makes UpdateCtx include nil pointer fields as explicit NULL values,
+// instead of skipping them.
+func WithNullableFields() UpdateOption {
+	return func(o *updateOptions) {
+		o.nilPointers = true
+	}
+}
+
+// WithTimestamps makes InsertCtx fill createdCol and updatedCol, and UpdateCtx
+// fill updatedCol, with time.Now() when the db tagged struct declares the
+// column and the caller left it zero. Structs without these columns are
+// untouched, so the option is safe to set once and reuse across tables.
+func WithTimestamps(createdCol, updatedCol string) UpdateOption {
+	return func(o *updateOptions) {
+		o.createdCol = createdCol
+		o.updatedCol = updatedCol
+	}
+}
+
+// UpdateCtx builds an UPDATE statement from the non-zero db tagged fields of set,
+// and executes it on given session, returning the affected rows.
+// Pointer fields express intent explicitly: non-nil pointers are always included,
+// and nil pointers are skipped unless WithNullableFields is given.
+func UpdateCtx(ctx context.Context, session Session, table string, set interface{},
+	where string, whereArgs ...interface{}) (int64, error) {
+	return UpdateWithOptionsCtx(ctx, session, table, set, where, whereArgs)
+}
+
+// UpdateWithOptionsCtx is UpdateCtx with customized options.
+func UpdateWithOptionsCtx(ctx context.Context, session Session, table string, set interface{},
+	where string, whereArgs []interface{}, opts ...UpdateOption) (int64, error) {
+	var o updateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	columns, args, err := buildSetClauseWithOptions(set, o)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(o.updatedCol) > 0 && hasTaggedColumn(set, o.updatedCol) &&
+		!containsColumn(columns, o.updatedCol) {
+		columns = append(columns, o.updatedCol)
+		args = append(args, time.Now())
+	}
+
+	return updateCtx(ctx, session, table, columns, args, where, whereArgs...)
+}
+
+func updateCtx(ctx context.Context, session Session, table string, columns []string,
+	args []interface{}, where string, whereArgs ...interface{}) (int64, error) {
+	var builder strings.Builder
+	builder.WriteString("UPDATE ")
+	builder.WriteString(table)
+	builder.WriteString(" SET ")
+	for i, column := range columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(column)
+		builder.WriteString(" = ?")
+	}
+	if len(where) > 0 {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(where)
+	}
+
+	result, err := session.ExecCtx(ctx, builder.String(), append(args, whereArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func buildSetClauseWithOptions(set interface{}, o updateOptions) ([]string, []interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(set))
+	if v.Kind() != reflect.Struct {
+		return nil, nil, ErrUnsupportedValueType
+	}
+
+	var columns []string
+	var args []interface{}
+	rt := mapping.Deref(v.Type())
+	for i := 0; i < rt.NumField(); i++ {
+		key := parseTagName(rt.Field(i))
+		if len(key) == 0 {
+			continue
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Ptr:
+			if field.IsNil() {
+				if !o.nilPointers {
+					continue
+				}
+				columns = append(columns, key)
+				args = append(args, nil)
+			} else {
+				columns = append(columns, key)
+				args = append(args, field.Elem().Interface())
+			}
+		default:
+			if field.IsZero() {
+				continue
+			}
+			columns = append(columns, key)
+			args = append(args, field.Interface())
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, nil, ErrNoColumnsToUpdate
+	}
+
+	return columns, args, nil
+}
@@ -0,0 +1,66 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSetClause(t *testing.T) {
+	name := "kevin"
+	var user struct {
+		Name    *string `db:"name"`
+		Age     int     `db:"age"`
+		Mobile  string  `db:"mobile"`
+		Ignored string
+	}
+	user.Name = &name
+	user.Age = 20
+
+	columns, args, err := buildSetClauseWithOptions(&user, updateOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name", "age"}, columns)
+	assert.Equal(t, []interface{}{"kevin", 20}, args)
+}
+
+func TestBuildSetClauseNilPointers(t *testing.T) {
+	var user struct {
+		Name *string `db:"name"`
+		Age  int     `db:"age"`
+	}
+
+	_, _, err := buildSetClauseWithOptions(&user, updateOptions{})
+	assert.Equal(t, ErrNoColumnsToUpdate, err)
+
+	columns, args, err := buildSetClauseWithOptions(&user, updateOptions{nilPointers: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name"}, columns)
+	assert.Equal(t, []interface{}{nil}, args)
+}
+
+func TestBuildSetClauseNonStruct(t *testing.T) {
+	_, _, err := buildSetClauseWithOptions("not a struct", updateOptions{})
+	assert.Equal(t, ErrUnsupportedValueType, err)
+}
+
+func TestUpdateCtx(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`UPDATE users SET name = \?, age = \? WHERE id = \?`).
+			WithArgs("kevin", 20, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var user struct {
+			Name string `db:"name"`
+			Age  int    `db:"age"`
+		}
+		user.Name = "kevin"
+		user.Age = 20
+
+		conn := NewSqlConnFromDB(db)
+		affected, err := UpdateCtx(context.Background(), conn, "users", &user, "id = ?", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), affected)
+	})
+}
@@ -0,0 +1,55 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrUpsertReturningUnsupported is an error that indicates the upsert action
+// reporting is requested on a driver other than postgres.
+var ErrUpsertReturningUnsupported = errors.New("upsert returning is only supported on postgres")
+
+// UpsertReturning runs an upsert and reports the per-row action, like
+// UpsertReturningCtx.
+func UpsertReturning(conn SqlConn, query string, args ...interface{}) ([]bool, error) {
+	return UpsertReturningCtx(context.Background(), conn, query, args...)
+}
+
+// UpsertReturningCtx runs an `INSERT ... ON CONFLICT` upsert and reports for
+// each row whether it was inserted (true) or updated (false), so sync jobs can
+// emit accurate created/updated events. It appends
+// `RETURNING (xmax = 0) AS inserted` to query unless it already has a
+// RETURNING clause, relying on the postgres xmax system column, so it's
+// postgres only. As a write, the statement always runs on the master.
+func UpsertReturningCtx(ctx context.Context, conn SqlConn, query string,
+	args ...interface{}) ([]bool, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return nil, err
+	}
+	if db.driverName != postgresDriverName {
+		return nil, ErrUpsertReturningUnsupported
+	}
+
+	if !strings.Contains(strings.ToUpper(query), "RETURNING") {
+		query += " RETURNING (xmax = 0) AS inserted"
+	}
+
+	var inserted []bool
+	if err := db.queryRows(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			var b bool
+			if err := rows.Scan(&b); err != nil {
+				return err
+			}
+			inserted = append(inserted, b)
+		}
+		return rows.Err()
+	}, query, args...); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
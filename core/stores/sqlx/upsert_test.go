@@ -0,0 +1,37 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/breaker"
+)
+
+func TestUpsertReturning(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`INSERT INTO users .+ ON CONFLICT .+ RETURNING \(xmax = 0\) AS inserted`).
+			WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(true).AddRow(false))
+
+		conn := &commonSqlConn{
+			driverName: postgresDriverName,
+			connProv: func(ds string) (*sql.DB, error) {
+				return db, nil
+			},
+			brk: breaker.NewBreaker(),
+		}
+		inserted, err := UpsertReturning(conn,
+			"INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4) "+
+				"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name",
+			1, "first", 2, "second")
+		assert.Nil(t, err)
+		assert.Equal(t, []bool{true, false}, inserted)
+	})
+}
+
+func TestUpsertReturningUnsupportedDriver(t *testing.T) {
+	conn := &commonSqlConn{driverName: mysqlDriverName}
+	_, err := UpsertReturning(conn, "INSERT INTO users (id) VALUES (?)")
+	assert.Equal(t, ErrUpsertReturningUnsupported, err)
+}
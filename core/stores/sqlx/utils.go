@@ -2,10 +2,12 @@ package sqlx
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/mapping"
@@ -50,12 +52,29 @@ func escape(input string) string {
 	return b.String()
 }
 
+func containsNamedArgs(args []interface{}) bool {
+	for _, arg := range args {
+		switch arg.(type) {
+		case sql.NamedArg, *sql.NamedArg:
+			return true
+		}
+	}
+
+	return false
+}
+
 func format(query string, args ...interface{}) (string, error) {
 	numArgs := len(args)
 	if numArgs == 0 {
 		return query, nil
 	}
 
+	// named args use driver-specific placeholders that positional matching
+	// can't interpolate, pass the query through untouched for logging.
+	if containsNamedArgs(args) {
+		return query, nil
+	}
+
 	var b strings.Builder
 	var argIndex int
 	bytes := len(query)
@@ -129,6 +148,22 @@ func format(query string, args ...interface{}) (string, error) {
 	return b.String(), nil
 }
 
+// wrapDeadlineError wraps deadline-exceeded errors with the effective limit and
+// the elapsed time, so timeouts can be tuned from logs.
+func wrapDeadlineError(ctx context.Context, err error, elapsed time.Duration) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return err
+	}
+
+	limit := deadline.Sub(time.Now().Add(-elapsed))
+	return fmt.Errorf("query exceeded deadline: limit=%v elapsed=%v: %w", limit, elapsed, err)
+}
+
 func logInstanceError(datasource string, err error) {
 	datasource = desensitize(datasource)
 	logx.Errorf("Error on getting sql instance of %s: %v", datasource, err)
@@ -136,7 +171,7 @@ func logInstanceError(datasource string, err error) {
 
 func logSqlError(ctx context.Context, stmt string, err error) {
 	if err != nil && err != ErrNotFound {
-		logx.WithContext(ctx).Errorf("stmt: %s, error: %s", stmt, err.Error())
+		logx.WithContext(ctx).Errorf("stmt: %s, error: %s", truncateLogQuery(ctx, stmt), err.Error())
 	}
 }
 
@@ -1,6 +1,11 @@
 package sqlx
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"strings"
 	"testing"
 
@@ -138,3 +143,32 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapDeadlineError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	assert.Nil(t, wrapDeadlineError(ctx, nil, time.Millisecond))
+	other := errors.New("other")
+	assert.Equal(t, other, wrapDeadlineError(ctx, other, time.Millisecond))
+
+	err := wrapDeadlineError(ctx, context.DeadlineExceeded, 2*time.Millisecond)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "query exceeded deadline")
+
+	// without a deadline the error is returned untouched
+	assert.Equal(t, context.DeadlineExceeded,
+		wrapDeadlineError(context.Background(), context.DeadlineExceeded, time.Millisecond))
+}
+
+func TestFormatNamedArgs(t *testing.T) {
+	query := "select name from users where id = @id"
+	val, err := format(query, sql.Named("id", 5))
+	assert.Nil(t, err)
+	assert.Equal(t, query, val)
+
+	named := sql.Named("id", 5)
+	val, err = format(query, &named)
+	assert.Nil(t, err)
+	assert.Equal(t, query, val)
+}
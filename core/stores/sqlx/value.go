@@ -0,0 +1,120 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// assignScanValue assigns a driver scan value to dest, a pointer to the real
+// destination. It mirrors the conversions database/sql applies, for destinations
+// that are wrapped by an intermediate scanner.
+func assignScanValue(dest, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return ErrNotSettable
+	}
+
+	dv = reflect.Indirect(dv)
+	if src == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	}
+
+	if t, ok := src.(time.Time); ok {
+		if dv.Type() == reflect.TypeOf(time.Time{}) {
+			dv.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(scanValueString(src))
+		return nil
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			dv.SetBool(v)
+			return nil
+		case int64:
+			dv.SetBool(v != 0)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := src.(type) {
+		case int64:
+			dv.SetInt(v)
+			return nil
+		default:
+			i, err := strconv.ParseInt(scanValueString(src), 10, 64)
+			if err == nil {
+				dv.SetInt(i)
+				return nil
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := src.(type) {
+		case int64:
+			dv.SetUint(uint64(v))
+			return nil
+		default:
+			u, err := strconv.ParseUint(scanValueString(src), 10, 64)
+			if err == nil {
+				dv.SetUint(u)
+				return nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := src.(type) {
+		case float64:
+			dv.SetFloat(v)
+			return nil
+		default:
+			f, err := strconv.ParseFloat(scanValueString(src), 64)
+			if err == nil {
+				dv.SetFloat(f)
+				return nil
+			}
+		}
+	case reflect.Slice:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			if b, ok := src.([]byte); ok {
+				buf := make([]byte, len(b))
+				copy(buf, b)
+				dv.SetBytes(buf)
+				return nil
+			}
+			dv.SetBytes([]byte(scanValueString(src)))
+			return nil
+		}
+	case reflect.Interface:
+		dv.Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().ConvertibleTo(dv.Type()) {
+		dv.Set(sv.Convert(dv.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("unsupported scan, storing %T into %T", src, dest)
+}
+
+func scanValueString(src interface{}) string {
+	switch v := src.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
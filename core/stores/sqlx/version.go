@@ -0,0 +1,130 @@
+package sqlx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Capabilities describes the features the backend supports, derived from the
+// driver and the server version, so callers can pick the right syntax for
+// RETURNING, COPY or SKIP LOCKED instead of guessing from the driver name.
+type Capabilities struct {
+	// ServerVersion is the raw version string reported by the server.
+	ServerVersion string
+	// MariaDB reports whether a mysql-driver backend is actually MariaDB.
+	MariaDB bool
+	// Returning reports support for the RETURNING clause.
+	Returning bool
+	// Copy reports support for the COPY protocol.
+	Copy bool
+	// SkipLocked reports support for SELECT ... FOR UPDATE SKIP LOCKED.
+	SkipLocked bool
+}
+
+// ServerVersion reports the backend's version string, queried once per
+// datasource and cached for the lifetime of the pool.
+func ServerVersion(ctx context.Context, conn SqlConn) (string, error) {
+	caps, err := ServerCapabilities(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+
+	return caps.ServerVersion, nil
+}
+
+// ServerCapabilities reports what the backend supports, derived from the
+// driver and the server version. The version is queried on first use and
+// cached per datasource, so the lookup is free on the hot path.
+func ServerCapabilities(ctx context.Context, conn SqlConn) (Capabilities, error) {
+	db, err := asCommonSqlConn(conn)
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	datasource, err := db.DataSourceResp("")
+	if err != nil {
+		logInstanceError(datasource, err)
+		return Capabilities{}, err
+	}
+
+	db.capsLock.Lock()
+	defer db.capsLock.Unlock()
+
+	if caps, ok := db.caps[datasource]; ok {
+		return caps, nil
+	}
+
+	pool, err := db.connProv(datasource)
+	if err != nil {
+		db.onError(datasource, err)
+		return Capabilities{}, err
+	}
+
+	var version string
+	if err := pool.QueryRowContext(ctx, versionQuery(db.driverName)).Scan(&version); err != nil {
+		return Capabilities{}, err
+	}
+
+	caps := deriveCapabilities(db.driverName, version)
+	if db.caps == nil {
+		db.caps = make(map[string]Capabilities)
+	}
+	db.caps[datasource] = caps
+
+	return caps, nil
+}
+
+func versionQuery(driverName string) string {
+	if driverName == postgresDriverName {
+		return "SHOW server_version"
+	}
+
+	return "SELECT VERSION()"
+}
+
+// deriveCapabilities maps a driver and server version to feature support.
+func deriveCapabilities(driverName, version string) Capabilities {
+	caps := Capabilities{ServerVersion: version}
+	major, minor := parseServerVersion(version)
+
+	switch driverName {
+	case postgresDriverName:
+		caps.Returning = true
+		caps.Copy = true
+		caps.SkipLocked = major > 9 || (major == 9 && minor >= 5)
+	case mysqlDriverName:
+		caps.MariaDB = strings.Contains(strings.ToLower(version), "mariadb")
+		if caps.MariaDB {
+			caps.Returning = major > 10 || (major == 10 && minor >= 5)
+			caps.SkipLocked = major > 10 || (major == 10 && minor >= 6)
+		} else {
+			caps.SkipLocked = major >= 8
+		}
+	}
+
+	return caps
+}
+
+// parseServerVersion extracts the leading major.minor pair from a version
+// string like "8.0.32" or "10.6.12-MariaDB-1:10.6.12+maria~ubu2004".
+func parseServerVersion(version string) (major, minor int) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) > 0 {
+		major, _ = strconv.Atoi(leadingDigits(parts[0]))
+	}
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(leadingDigits(parts[1]))
+	}
+
+	return major, minor
+}
+
+func leadingDigits(s string) string {
+	var i int
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[:i]
+}
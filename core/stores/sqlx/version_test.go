@@ -0,0 +1,115 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerVersionCached(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT VERSION").WillReturnRows(
+			sqlmock.NewRows([]string{"version()"}).AddRow("8.0.32"))
+
+		conn := NewSqlConnFromDB(db)
+		version, err := ServerVersion(context.Background(), conn)
+		assert.Nil(t, err)
+		assert.Equal(t, "8.0.32", version)
+
+		// the second lookup is served from the cache, no query expected
+		version, err = ServerVersion(context.Background(), conn)
+		assert.Nil(t, err)
+		assert.Equal(t, "8.0.32", version)
+	})
+}
+
+func TestServerVersionUnsupportedConn(t *testing.T) {
+	_, err := ServerVersion(context.Background(), new(mockedConn))
+	assert.Equal(t, ErrUnsupportedConn, err)
+}
+
+func TestDeriveCapabilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		version string
+		want    Capabilities
+	}{
+		{
+			name:    "mysql 8",
+			driver:  mysqlDriverName,
+			version: "8.0.32",
+			want:    Capabilities{ServerVersion: "8.0.32", SkipLocked: true},
+		},
+		{
+			name:    "mysql 5.7",
+			driver:  mysqlDriverName,
+			version: "5.7.40",
+			want:    Capabilities{ServerVersion: "5.7.40"},
+		},
+		{
+			name:    "mariadb 10.6",
+			driver:  mysqlDriverName,
+			version: "10.6.12-MariaDB-1:10.6.12+maria~ubu2004",
+			want: Capabilities{
+				ServerVersion: "10.6.12-MariaDB-1:10.6.12+maria~ubu2004",
+				MariaDB:       true,
+				Returning:     true,
+				SkipLocked:    true,
+			},
+		},
+		{
+			name:    "mariadb 10.4",
+			driver:  mysqlDriverName,
+			version: "10.4.28-MariaDB",
+			want: Capabilities{
+				ServerVersion: "10.4.28-MariaDB",
+				MariaDB:       true,
+			},
+		},
+		{
+			name:    "postgres 14",
+			driver:  postgresDriverName,
+			version: "14.5",
+			want: Capabilities{
+				ServerVersion: "14.5",
+				Returning:     true,
+				Copy:          true,
+				SkipLocked:    true,
+			},
+		},
+		{
+			name:    "postgres 9.4",
+			driver:  postgresDriverName,
+			version: "9.4.26",
+			want: Capabilities{
+				ServerVersion: "9.4.26",
+				Returning:     true,
+				Copy:          true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, deriveCapabilities(test.driver, test.version))
+		})
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	major, minor := parseServerVersion("8.0.32")
+	assert.Equal(t, 8, major)
+	assert.Equal(t, 0, minor)
+
+	major, minor = parseServerVersion("10.6.12-MariaDB")
+	assert.Equal(t, 10, major)
+	assert.Equal(t, 6, minor)
+
+	major, minor = parseServerVersion("garbage")
+	assert.Equal(t, 0, major)
+	assert.Equal(t, 0, minor)
+}
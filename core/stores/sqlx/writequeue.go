@@ -0,0 +1,208 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/breaker"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/syncx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const defaultReplayInterval = time.Second
+
+// ErrWriteQueued is returned when master is unreachable and the write was
+// persisted to the write queue for later replay instead of being executed.
+var ErrWriteQueued = errors.New("master unreachable, write queued for replay")
+
+type (
+	// A QueuedWrite is a write statement persisted for replay.
+	QueuedWrite struct {
+		Query string
+		Args  []interface{}
+	}
+
+	// A WriteQueue stores writes while master is down. Implementations
+	// decide durability, a database-backed store survives restarts while
+	// MemoryWriteQueue doesn't.
+	WriteQueue interface {
+		Enqueue(w QueuedWrite) error
+		// Dequeue pops the oldest write, reporting false when empty.
+		Dequeue() (QueuedWrite, bool, error)
+	}
+
+	queueableKey struct{}
+
+	writeReplayer struct {
+		queue    WriteQueue
+		db       *commonSqlConn
+		interval time.Duration
+		running  *syncx.AtomicBool
+		// pending holds a dequeued write that failed on connectivity, it's
+		// retried before anything else so replay order is preserved. Only the
+		// single replay goroutine touches it.
+		pending *QueuedWrite
+	}
+)
+
+// WithWriteQueue persists writes to store when master is unreachable, and
+// replays them in order once it recovers. Only writes explicitly marked
+// non-critical via QueueableWriteCtx are queued, such calls get ErrWriteQueued
+// instead of executing. This trades immediacy for availability, use it only
+// for writes that tolerate eventual consistency.
+func WithWriteQueue(store WriteQueue) SqlOption {
+	return func(conn *commonSqlConn) {
+		conn.writeQueue = &writeReplayer{
+			queue:    store,
+			db:       conn,
+			interval: defaultReplayInterval,
+			running:  syncx.NewAtomicBool(),
+		}
+	}
+}
+
+// QueueableWriteCtx marks the writes on the returned context as non-critical,
+// allowing them to be queued for replay when master is unreachable.
+func QueueableWriteCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queueableKey{}, true)
+}
+
+func queueableWrite(ctx context.Context) bool {
+	on, ok := ctx.Value(queueableKey{}).(bool)
+	return ok && on
+}
+
+// maybeQueueWrite persists the failed write when it qualifies for queueing,
+// reporting whether it did.
+func (db *commonSqlConn) maybeQueueWrite(ctx context.Context, err error, q string,
+	args []interface{}) bool {
+	if db.writeQueue == nil || !queueableWrite(ctx) || !isConnectivityError(err) {
+		return false
+	}
+
+	if qerr := db.writeQueue.queue.Enqueue(QueuedWrite{
+		Query: q,
+		Args:  args,
+	}); qerr != nil {
+		logx.Errorf("failed to queue write %q: %v", q, qerr)
+		return false
+	}
+
+	db.writeQueue.ensureReplaying()
+	return true
+}
+
+// ensureReplaying starts the replay loop if it's not already running.
+func (w *writeReplayer) ensureReplaying() {
+	if !w.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	threading.GoSafe(func() {
+		defer w.running.Set(false)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if done := w.replay(); done {
+				return
+			}
+		}
+	})
+}
+
+// replay drains the queue onto master, reporting true when the queue is empty.
+// On a connectivity error the failed write is held back and retried first on
+// the next tick, so replay never reorders writes.
+func (w *writeReplayer) replay() bool {
+	datasource, err := w.db.DataSourceResp("")
+	if err != nil {
+		return false
+	}
+
+	conn, err := w.db.connProv(datasource)
+	if err != nil {
+		return false
+	}
+
+	for {
+		var write QueuedWrite
+		if w.pending != nil {
+			write = *w.pending
+		} else {
+			var ok bool
+			write, ok, err = w.queue.Dequeue()
+			if err != nil {
+				logx.Errorf("failed to dequeue write: %v", err)
+				return false
+			}
+			if !ok {
+				return true
+			}
+		}
+
+		if _, err := exec(context.Background(), conn, write.Query, write.Args...); err != nil {
+			if isConnectivityError(err) {
+				// re-enqueueing would put the write behind newer ones, hold it
+				// instead so it leads the next replay attempt
+				w.pending = &write
+				return false
+			}
+
+			// a statement error won't heal on retry, drop it to unblock the queue
+			logx.Errorf("dropping queued write %q: %v", write.Query, err)
+		}
+
+		w.pending = nil
+	}
+}
+
+func isConnectivityError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var ne net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, breaker.ErrServiceUnavailable) ||
+		errors.As(err, &ne)
+}
+
+// MemoryWriteQueue is an in-memory WriteQueue, for tests and for callers that
+// accept losing queued writes on process exit.
+type MemoryWriteQueue struct {
+	lock   sync.Mutex
+	writes []QueuedWrite
+}
+
+// NewMemoryWriteQueue returns a MemoryWriteQueue.
+func NewMemoryWriteQueue() *MemoryWriteQueue {
+	return new(MemoryWriteQueue)
+}
+
+// Enqueue appends w to the queue.
+func (q *MemoryWriteQueue) Enqueue(w QueuedWrite) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.writes = append(q.writes, w)
+	return nil
+}
+
+// Dequeue pops the oldest write, reporting false when empty.
+func (q *MemoryWriteQueue) Dequeue() (QueuedWrite, bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.writes) == 0 {
+		return QueuedWrite{}, false, nil
+	}
+
+	write := q.writes[0]
+	q.writes = q.writes[1:]
+	return write, true, nil
+}
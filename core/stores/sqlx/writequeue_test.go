@@ -0,0 +1,119 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryWriteQueue(t *testing.T) {
+	q := NewMemoryWriteQueue()
+	_, ok, err := q.Dequeue()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, q.Enqueue(QueuedWrite{Query: "first"}))
+	assert.Nil(t, q.Enqueue(QueuedWrite{Query: "second"}))
+
+	write, ok, err := q.Dequeue()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "first", write.Query)
+}
+
+func TestQueueableWriteCtx(t *testing.T) {
+	assert.False(t, queueableWrite(context.Background()))
+	assert.True(t, queueableWrite(QueueableWriteCtx(context.Background())))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "dial tcp: connection timed out" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsConnectivityError(t *testing.T) {
+	assert.False(t, isConnectivityError(nil))
+	assert.False(t, isConnectivityError(errors.New("syntax error")))
+	assert.True(t, isConnectivityError(driver.ErrBadConn))
+	assert.True(t, isConnectivityError(timeoutError{}))
+}
+
+func TestExecQueuesWriteOnBadConn(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnError(timeoutError{})
+
+		store := NewMemoryWriteQueue()
+		conn := NewSqlConnFromDB(db, WithWriteQueue(store))
+
+		_, err := conn.ExecCtx(QueueableWriteCtx(context.Background()),
+			"update users set name = 'tom' where id = 1")
+		assert.Equal(t, ErrWriteQueued, err)
+
+		write, ok, err := store.Dequeue()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "update users set name = 'tom' where id = 1", write.Query)
+	})
+}
+
+func TestExecNotQueuedWithoutMarker(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnError(timeoutError{})
+
+		store := NewMemoryWriteQueue()
+		conn := NewSqlConnFromDB(db, WithWriteQueue(store))
+
+		_, err := conn.ExecCtx(context.Background(), "update users set name = 'tom'")
+		assert.NotNil(t, err)
+		assert.NotEqual(t, ErrWriteQueued, err)
+
+		_, ok, qerr := store.Dequeue()
+		assert.Nil(t, qerr)
+		assert.False(t, ok)
+	})
+}
+
+func TestWriteReplayerReplay(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from users").WillReturnError(errors.New("syntax error"))
+
+		store := NewMemoryWriteQueue()
+		conn := NewSqlConnFromDB(db, WithWriteQueue(store))
+		c, err := asCommonSqlConn(conn)
+		assert.Nil(t, err)
+
+		assert.Nil(t, store.Enqueue(QueuedWrite{Query: "update users set name = 'tom'"}))
+		// a statement error is dropped so the queue doesn't block
+		assert.Nil(t, store.Enqueue(QueuedWrite{Query: "delete from users where"}))
+
+		assert.True(t, c.writeQueue.replay())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWriteReplayerReplayKeepsOrderOnConnectivityError(t *testing.T) {
+	runOrmTest(t, func(db *sql.DB, mock sqlmock.Sqlmock) {
+		mock.ExpectExec("update users").WillReturnError(timeoutError{})
+		mock.ExpectExec("update users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("update orders").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		store := NewMemoryWriteQueue()
+		conn := NewSqlConnFromDB(db, WithWriteQueue(store))
+		c, err := asCommonSqlConn(conn)
+		assert.Nil(t, err)
+
+		assert.Nil(t, store.Enqueue(QueuedWrite{Query: "update users set name = 'tom'"}))
+		assert.Nil(t, store.Enqueue(QueuedWrite{Query: "update orders set total = 1"}))
+
+		// the failed write is held back, not requeued behind newer ones
+		assert.False(t, c.writeQueue.replay())
+		assert.True(t, c.writeQueue.replay())
+	})
+}